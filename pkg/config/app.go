@@ -1,22 +1,127 @@
 package config
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+
 	"github.com/jinzhu/gorm"
 	_ "github.com/jinzhu/gorm/dialects/mysql"
+	_ "github.com/jinzhu/gorm/dialects/postgres"
 )
 
+// ErrNotConnected is returned by helpers that need an open connection when
+// Connect has not succeeded.
+var ErrNotConnected = errors.New("config: database not connected")
+
 var (
+	mu sync.RWMutex
 	db *gorm.DB
+	// dialed holds the arguments of the last successful open so the
+	// connection can be re-established by StartAutoReconnect.
+	dialed *dialArgs
 )
 
-func Connect() {
-	d, err := gorm.Open("mysql", "your_username:your_password@tcp(db-xxxxx.clever-cloud.com:3306)/your_database?charset=utf8&parseTime=True&loc=Local")
+type dialArgs struct {
+	dialect string
+	dsn     string
+	pool    PoolConfig
+}
+
+// Connect opens the connection with the default pool settings.
+func Connect() error {
+	return ConnectWithPool(PoolConfig{})
+}
+
+// ConnectWithPool opens the connection and applies cfg to its pool. It is
+// safe to call from multiple goroutines; once a connection is open further
+// calls are no-ops until Close.
+func ConnectWithPool(cfg PoolConfig) error {
+	dsn, err := BuildDSN()
 	if err != nil {
-		panic(err)
+		return err
 	}
+	return open(DialectMySQL, dsn, cfg)
+}
+
+func open(dialect, dsn string, cfg PoolConfig) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if db != nil {
+		return nil
+	}
+	d, err := dial(dialect, dsn, cfg)
+	if err != nil {
+		return err
+	}
+	applySettings(d)
 	db = d
+	dialed = &dialArgs{dialect: dialect, dsn: dsn, pool: cfg}
+	return nil
+}
+
+// dial opens a connection and applies cfg to its pool.
+func dial(dialect, dsn string, cfg PoolConfig) (*gorm.DB, error) {
+	d, err := gorm.Open(dialect, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("config: failed to open %s connection to %s: %w", dialect, MaskDSN(dsn), err)
+	}
+	cfg.apply(d)
+	return d, nil
 }
 
+// GetBD returns the connection opened by Connect, or nil if Connect has not
+// succeeded yet.
 func GetBD() *gorm.DB {
+	mu.RLock()
+	defer mu.RUnlock()
 	return db
 }
+
+// SQLDB returns the *sql.DB behind the package connection, or
+// ErrNotConnected if Connect has not succeeded.
+func SQLDB() (*sql.DB, error) {
+	d := GetBD()
+	if d == nil {
+		return nil, ErrNotConnected
+	}
+	return d.DB(), nil
+}
+
+// Close releases the connection and any read replicas so a later Connect
+// can open a fresh one. It is a no-op when nothing is connected.
+func Close() error {
+	mu.Lock()
+	defer mu.Unlock()
+	err := closeReplicas()
+	if db == nil {
+		return err
+	}
+	if cerr := db.Close(); cerr != nil {
+		err = cerr
+	}
+	db = nil
+	return err
+}
+
+// forEachConn calls fn for the primary, every replica and every named
+// connection. It must be called with mu held.
+func forEachConn(fn func(*gorm.DB)) {
+	if db != nil {
+		fn(db)
+	}
+	for _, r := range replicas {
+		fn(r)
+	}
+	for _, d := range named {
+		fn(d)
+	}
+}
+
+// applySettings applies the logging and naming settings to a new
+// connection. It must be called with mu held.
+func applySettings(d *gorm.DB) {
+	applyLogging(d)
+	applyNaming(d)
+}
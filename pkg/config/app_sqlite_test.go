@@ -0,0 +1,76 @@
+//go:build sqlite
+
+package config
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+)
+
+func TestClose(t *testing.T) {
+	if _, err := ConnectForTesting(); err != nil {
+		t.Fatal(err)
+	}
+	if err := Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+	if d := GetBD(); d != nil {
+		t.Errorf("GetBD() = %v after Close, want nil", d)
+	}
+	if err := Close(); err != nil {
+		t.Errorf("second Close() = %v, want nil", err)
+	}
+}
+
+// TestOpenConcurrent races the first open, which Connect funnels into,
+// against readers. Only one connection may win and no reader may see a
+// different one.
+func TestOpenConcurrent(t *testing.T) {
+	defer Close()
+	var (
+		wg   sync.WaitGroup
+		seen = make([]*gorm.DB, 50)
+	)
+	for i := range seen {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := open("sqlite3", ":memory:", PoolConfig{}); err != nil {
+				t.Error(err)
+			}
+			seen[i] = GetBD()
+		}(i)
+	}
+	wg.Wait()
+
+	want := GetBD()
+	if want == nil {
+		t.Fatal("GetBD() = nil after open")
+	}
+	for i, d := range seen {
+		if d != want {
+			t.Errorf("goroutine %d saw connection %p, want %p", i, d, want)
+		}
+	}
+}
+
+func TestSQLDB(t *testing.T) {
+	if _, err := SQLDB(); !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("SQLDB() before Connect = %v, want ErrNotConnected", err)
+	}
+
+	d := connectTest(t)
+	sqlDB, err := SQLDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sqlDB != d.DB() {
+		t.Error("SQLDB() is not the pool behind GetBD()")
+	}
+	if err := sqlDB.Ping(); err != nil {
+		t.Errorf("Ping() = %v", err)
+	}
+}
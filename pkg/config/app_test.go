@@ -0,0 +1,59 @@
+package config
+
+import (
+	"sync"
+	"testing"
+)
+
+// setDBEnv points BuildDSN at a MySQL server on 127.0.0.1:port.
+func setDBEnv(t *testing.T, port, params string) {
+	t.Helper()
+	t.Setenv("DB_USER", "bookstore")
+	t.Setenv("DB_PASSWORD", "secret")
+	t.Setenv("DB_HOST", "127.0.0.1")
+	t.Setenv("DB_PORT", port)
+	t.Setenv("DB_NAME", "bookstore")
+	t.Setenv("DB_PARAMS", params)
+}
+
+func TestConnectBadDSN(t *testing.T) {
+	tests := []struct {
+		name   string
+		port   string
+		params string
+	}{
+		// Nothing listens on port 1, so the initial ping is refused.
+		{"unreachable server", "1", ""},
+		{"malformed params", "3306", "parseTime=maybe"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setDBEnv(t, tt.port, tt.params)
+			if err := Connect(); err == nil {
+				Close()
+				t.Fatal("Connect() succeeded, want an error")
+			}
+			if d := GetBD(); d != nil {
+				t.Errorf("GetBD() = %v after a failed Connect, want nil", d)
+			}
+		})
+	}
+}
+
+func TestConnectConcurrent(t *testing.T) {
+	setDBEnv(t, "1", "")
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := Connect(); err == nil {
+				t.Error("Connect() succeeded against an unreachable server")
+			}
+			if d := GetBD(); d != nil {
+				t.Errorf("GetBD() = %v, want nil", d)
+			}
+		}()
+	}
+	wg.Wait()
+}
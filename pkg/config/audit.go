@@ -0,0 +1,48 @@
+package config
+
+import (
+	"context"
+
+	"github.com/jinzhu/gorm"
+)
+
+type auditUserKey struct{}
+
+func init() {
+	gorm.DefaultCallback.Create().Before("gorm:create").Register("config:audit_user", stampAuditUser(true))
+	gorm.DefaultCallback.Update().Before("gorm:update").Register("config:audit_user", stampAuditUser(false))
+}
+
+// SetAuditUser returns a copy of ctx carrying userID. Writes issued through
+// GetDBContext with that context set the CreatedBy (on create) and
+// UpdatedBy columns of models that have them.
+func SetAuditUser(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, auditUserKey{}, userID)
+}
+
+// AuditUser returns the user ID stored in ctx by SetAuditUser.
+func AuditUser(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(auditUserKey{}).(string)
+	return id, ok
+}
+
+func stampAuditUser(create bool) func(*gorm.Scope) {
+	return func(scope *gorm.Scope) {
+		ctx, ok := contextFrom(scope)
+		if !ok {
+			return
+		}
+		user, ok := AuditUser(ctx)
+		if !ok {
+			return
+		}
+		if create {
+			if _, ok := scope.FieldByName("CreatedBy"); ok {
+				scope.SetColumn("CreatedBy", user)
+			}
+		}
+		if _, ok := scope.FieldByName("UpdatedBy"); ok {
+			scope.SetColumn("UpdatedBy", user)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+//go:build sqlite
+
+package config
+
+import (
+	"context"
+	"testing"
+)
+
+type auditDoc struct {
+	ID        uint
+	Title     string
+	CreatedBy string
+	UpdatedBy string
+}
+
+func TestAuditUser(t *testing.T) {
+	d := connectTest(t, &auditDoc{})
+	ctx := SetAuditUser(context.Background(), "alice")
+	if id, ok := AuditUser(ctx); !ok || id != "alice" {
+		t.Fatalf("AuditUser() = %q, %v; want alice", id, ok)
+	}
+
+	doc := auditDoc{Title: "Draft"}
+	if err := GetDBContext(ctx).Create(&doc).Error; err != nil {
+		t.Fatal(err)
+	}
+	var got auditDoc
+	d.First(&got, doc.ID)
+	if got.CreatedBy != "alice" || got.UpdatedBy != "alice" {
+		t.Errorf("after create by alice: CreatedBy %q, UpdatedBy %q", got.CreatedBy, got.UpdatedBy)
+	}
+
+	ctx = SetAuditUser(context.Background(), "bob")
+	if err := GetDBContext(ctx).Model(&got).Update("title", "Final").Error; err != nil {
+		t.Fatal(err)
+	}
+	d.First(&got, doc.ID)
+	if got.CreatedBy != "alice" || got.UpdatedBy != "bob" {
+		t.Errorf("after update by bob: CreatedBy %q, UpdatedBy %q; want alice, bob", got.CreatedBy, got.UpdatedBy)
+	}
+}
+
+func TestAuditUserUnset(t *testing.T) {
+	d := connectTest(t, &auditDoc{})
+	doc := auditDoc{Title: "Anonymous"}
+	if err := GetDBContext(context.Background()).Create(&doc).Error; err != nil {
+		t.Fatal(err)
+	}
+	var got auditDoc
+	d.First(&got, doc.ID)
+	if got.CreatedBy != "" || got.UpdatedBy != "" {
+		t.Errorf("CreatedBy %q, UpdatedBy %q without an audit user, want both empty", got.CreatedBy, got.UpdatedBy)
+	}
+	if _, ok := AuditUser(context.Background()); ok {
+		t.Error("AuditUser() reported a user on a bare context")
+	}
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
+
+const defaultChunkSize = 100
+
+// BatchCreate inserts records with one multi-row INSERT per chunk of
+// chunkSize rows (100 when chunkSize <= 0), all inside a single
+// transaction. Blank CreatedAt/UpdatedAt fields are stamped, but unlike
+// Create it does not run GORM callbacks, save associations or read back
+// auto-increment IDs.
+func BatchCreate[T any](ctx context.Context, records []T, chunkSize int) error {
+	if len(records) == 0 {
+		return nil
+	}
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+	d := GetDBContext(ctx)
+	if d == nil {
+		return ErrNotConnected
+	}
+
+	tx := d.BeginTx(ctx, nil)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	for start := 0; start < len(records); start += chunkSize {
+		end := min(start+chunkSize, len(records))
+		query, args, err := batchInsertSQL(tx, records[start:end])
+		if err != nil {
+			return err
+		}
+		if err := tx.Exec(query, args...).Error; err != nil {
+			return fmt.Errorf("config: inserting rows %d-%d: %w", start, end-1, err)
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+// batchInsertSQL builds a multi-row INSERT for rows. Primary keys and
+// columns with a database default are left out when they are blank in every
+// row, mirroring what Create does for a single row.
+func batchInsertSQL[T any](d *gorm.DB, rows []T) (string, []interface{}, error) {
+	now := gorm.NowFunc()
+	scopes := make([]*gorm.Scope, len(rows))
+	for i := range rows {
+		scopes[i] = d.NewScope(&rows[i])
+		for _, name := range []string{"CreatedAt", "UpdatedAt"} {
+			if f, ok := scopes[i].FieldByName(name); ok && f.IsBlank {
+				f.Set(now)
+			}
+		}
+	}
+
+	var (
+		columns []string
+		indexes []int
+	)
+	for i, f := range scopes[0].Fields() {
+		if !f.IsNormal || f.IsIgnored {
+			continue
+		}
+		if f.IsPrimaryKey || f.HasDefaultValue {
+			blank := 0
+			for _, s := range scopes {
+				if s.Fields()[i].IsBlank {
+					blank++
+				}
+			}
+			if blank == len(scopes) {
+				continue
+			}
+			if f.IsPrimaryKey && blank > 0 {
+				return "", nil, fmt.Errorf("config: primary key %s must be set on all rows or none", f.Name)
+			}
+		}
+		columns = append(columns, scopes[0].Quote(f.DBName))
+		indexes = append(indexes, i)
+	}
+
+	row := "(" + strings.TrimSuffix(strings.Repeat("?,", len(columns)), ",") + ")"
+	values := make([]string, len(scopes))
+	args := make([]interface{}, 0, len(scopes)*len(columns))
+	for r, s := range scopes {
+		values[r] = row
+		fields := s.Fields()
+		for _, i := range indexes {
+			args = append(args, fields[i].Field.Interface())
+		}
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s",
+		scopes[0].QuotedTableName(), strings.Join(columns, ","), strings.Join(values, ","))
+	return query, args, nil
+}
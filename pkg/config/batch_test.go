@@ -0,0 +1,76 @@
+//go:build sqlite
+
+package config
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+type batchItem struct {
+	ID        uint
+	Code      string `gorm:"unique_index"`
+	CreatedAt time.Time
+}
+
+func batchItems(n int) []batchItem {
+	items := make([]batchItem, n)
+	for i := range items {
+		items[i].Code = string(rune('a'+i%26)) + strings.Repeat("x", i/26)
+	}
+	return items
+}
+
+func TestBatchCreate(t *testing.T) {
+	d := connectTest(t, &batchItem{})
+	if err := BatchCreate(context.Background(), batchItems(250), 100); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRows(t, d, &batchItem{}); n != 250 {
+		t.Errorf("inserted %d rows, want 250", n)
+	}
+
+	var first batchItem
+	if err := d.First(&first).Error; err != nil {
+		t.Fatal(err)
+	}
+	if first.CreatedAt.IsZero() {
+		t.Error("CreatedAt was not stamped")
+	}
+}
+
+func TestBatchCreateEmpty(t *testing.T) {
+	// Empty input is a no-op and does not need a connection.
+	if err := BatchCreate[batchItem](context.Background(), nil, 100); err != nil {
+		t.Errorf("BatchCreate(nil) = %v, want nil", err)
+	}
+}
+
+// TestBatchCreateRollback fails the third chunk and checks that the
+// earlier chunks are rolled back with it.
+func TestBatchCreateRollback(t *testing.T) {
+	d := connectTest(t, &batchItem{})
+	items := batchItems(250)
+	items[240].Code = items[0].Code
+
+	err := BatchCreate(context.Background(), items, 100)
+	if err == nil || !strings.Contains(err.Error(), "rows 200-249") {
+		t.Fatalf("BatchCreate() = %v, want an error for rows 200-249", err)
+	}
+	if n := countRows(t, d, &batchItem{}); n != 0 {
+		t.Errorf("%d rows left after a failed batch, want 0", n)
+	}
+}
+
+func TestBatchCreatePartialKeys(t *testing.T) {
+	connectTest(t, &batchItem{})
+	items := batchItems(3)
+	items[1].ID = 7
+
+	err := BatchCreate(context.Background(), items, 0)
+	if err == nil || !strings.Contains(err.Error(), "primary key ID") {
+		t.Errorf("BatchCreate() = %v, want a primary key error", err)
+	}
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Callback kinds accepted by RegisterCallback and RemoveCallback.
+const (
+	CallbackCreate = "create"
+	CallbackQuery  = "query"
+	CallbackUpdate = "update"
+	CallbackDelete = "delete"
+)
+
+func callbackProcessor(kind string) (*gorm.CallbackProcessor, error) {
+	cb := gorm.DefaultCallback
+	switch kind {
+	case CallbackCreate:
+		return cb.Create(), nil
+	case CallbackQuery:
+		return cb.Query(), nil
+	case CallbackUpdate:
+		return cb.Update(), nil
+	case CallbackDelete:
+		return cb.Delete(), nil
+	}
+	return nil, fmt.Errorf("config: unsupported callback kind %q (supported: %s, %s, %s, %s)",
+		kind, CallbackCreate, CallbackQuery, CallbackUpdate, CallbackDelete)
+}
+
+// RegisterCallback runs fn right before every statement of the given kind
+// executes. fn receives the statement's session; its Value is the model
+// being written or queried. Callbacks are global to GORM and, like GORM's
+// own registration, should be set up during startup.
+func RegisterCallback(kind, name string, fn func(*gorm.DB)) error {
+	cp, err := callbackProcessor(kind)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		return errors.New("config: callback name is required")
+	}
+	if cp.Get(name) != nil {
+		return fmt.Errorf("config: %s callback %q is already registered", kind, name)
+	}
+	cp.Before("gorm:"+kind).Register(name, func(scope *gorm.Scope) {
+		fn(scope.DB())
+	})
+	return nil
+}
+
+// RemoveCallback removes a callback added with RegisterCallback.
+func RemoveCallback(kind, name string) error {
+	cp, err := callbackProcessor(kind)
+	if err != nil {
+		return err
+	}
+	if cp.Get(name) == nil {
+		return fmt.Errorf("config: no %s callback registered as %q", kind, name)
+	}
+	cp.Remove(name)
+	return nil
+}
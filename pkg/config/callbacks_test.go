@@ -0,0 +1,77 @@
+//go:build sqlite
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+)
+
+type cbItem struct {
+	ID   uint
+	Name string
+	Slug string
+}
+
+func slugify(d *gorm.DB) {
+	if it, ok := d.Value.(*cbItem); ok {
+		it.Slug = strings.ToLower(strings.ReplaceAll(it.Name, " ", "-"))
+	}
+}
+
+func TestRegisterCallback(t *testing.T) {
+	d := connectTest(t, &cbItem{})
+	if err := RegisterCallback(CallbackCreate, "test:slug", slugify); err != nil {
+		t.Fatal(err)
+	}
+	removed := false
+	t.Cleanup(func() {
+		if !removed {
+			RemoveCallback(CallbackCreate, "test:slug")
+		}
+	})
+
+	if err := RegisterCallback(CallbackCreate, "test:slug", slugify); err == nil {
+		t.Error("registering test:slug twice succeeded")
+	}
+
+	it := cbItem{Name: "Dune Messiah"}
+	if err := d.Create(&it).Error; err != nil {
+		t.Fatal(err)
+	}
+	var got cbItem
+	d.First(&got, it.ID)
+	if got.Slug != "dune-messiah" {
+		t.Errorf("stored slug = %q, want the callback to set dune-messiah", got.Slug)
+	}
+
+	if err := RemoveCallback(CallbackCreate, "test:slug"); err != nil {
+		t.Fatal(err)
+	}
+	removed = true
+	it = cbItem{Name: "Children of Dune"}
+	d.Create(&it)
+	if it.Slug != "" {
+		t.Errorf("slug = %q after RemoveCallback, want the callback gone", it.Slug)
+	}
+}
+
+func TestCallbackErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"register invalid kind", RegisterCallback("row", "test:x", slugify), `unsupported callback kind "row"`},
+		{"register empty name", RegisterCallback(CallbackQuery, "", slugify), "callback name is required"},
+		{"remove invalid kind", RemoveCallback("row", "test:x"), `unsupported callback kind "row"`},
+		{"remove unknown", RemoveCallback(CallbackDelete, "test:missing"), `no delete callback registered as "test:missing"`},
+	}
+	for _, tt := range tests {
+		if tt.err == nil || !strings.Contains(tt.err.Error(), tt.want) {
+			t.Errorf("%s: got %v, want an error containing %q", tt.name, tt.err, tt.want)
+		}
+	}
+}
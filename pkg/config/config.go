@@ -0,0 +1,243 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported values for Config.Dialect.
+const (
+	DialectMySQL    = "mysql"
+	DialectPostgres = "postgres"
+)
+
+// Supported values for Config.Network.
+const (
+	NetworkTCP  = "tcp"
+	NetworkUnix = "unix"
+)
+
+const (
+	defaultPostgresPort = 5432
+	defaultCharset      = "utf8mb4"
+	defaultCollation    = "utf8mb4_unicode_ci"
+)
+
+// Config describes a database connection. Zero values are omitted from the
+// DSN, except Dialect which defaults to mysql, Port which defaults to the
+// dialect's standard port, and Charset which defaults to utf8mb4 with the
+// utf8mb4_unicode_ci collation. Network selects "tcp" (the default, using
+// Host and Port) or "unix" (using Socket). AppName is sent as the
+// program_name connection attribute and defaults to the binary name.
+// Network, Charset, Collation, ParseTime, Loc, TLS, AppName and the timeouts
+// only apply to mysql.
+type Config struct {
+	Dialect   string     `json:"dialect" yaml:"dialect"`
+	User      string     `json:"user" yaml:"user"`
+	Password  string     `json:"password" yaml:"password"`
+	Host      string     `json:"host" yaml:"host"`
+	Port      int        `json:"port" yaml:"port"`
+	Network   string     `json:"network" yaml:"network"`
+	Socket    string     `json:"socket" yaml:"socket"`
+	Database  string     `json:"database" yaml:"database"`
+	Charset   string     `json:"charset" yaml:"charset"`
+	Collation string     `json:"collation" yaml:"collation"`
+	ParseTime bool       `json:"parseTime" yaml:"parseTime"`
+	Loc       string     `json:"loc" yaml:"loc"`
+	TLS       *TLSConfig `json:"tls" yaml:"tls"`
+	AppName   string     `json:"appName" yaml:"appName"`
+
+	// Dial, read and write timeouts passed to the mysql driver. Config
+	// files spell them as strings such as "10s"; JSON also accepts integer
+	// nanoseconds.
+	Timeout      time.Duration `json:"timeout" yaml:"timeout"`
+	ReadTimeout  time.Duration `json:"readTimeout" yaml:"readTimeout"`
+	WriteTimeout time.Duration `json:"writeTimeout" yaml:"writeTimeout"`
+
+	// Params holds any further driver parameters, appended to the DSN in
+	// key order, such as sslmode=disable for a postgres server without TLS.
+	Params map[string]string `json:"params" yaml:"params"`
+}
+
+func (c Config) dialect() string {
+	if c.Dialect == "" {
+		return DialectMySQL
+	}
+	return c.Dialect
+}
+
+// appName returns AppName, or the binary name when it is unset, stripped of
+// the ',' and ':' separators of the connectionAttributes parameter.
+func (c Config) appName() string {
+	name := c.AppName
+	if name == "" {
+		name = filepath.Base(os.Args[0])
+	}
+	return strings.NewReplacer(",", "_", ":", "_").Replace(name)
+}
+
+// Validate reports an unsupported dialect or the first required field that
+// is empty.
+func (c Config) Validate() error {
+	switch d := c.dialect(); d {
+	case DialectMySQL, DialectPostgres:
+	default:
+		return fmt.Errorf("config: unsupported dialect %q (supported: %s, %s)", d, DialectMySQL, DialectPostgres)
+	}
+	switch c.Network {
+	case "", NetworkTCP:
+		if c.Host == "" {
+			return errors.New("config: Host is required")
+		}
+	case NetworkUnix:
+		if c.Socket == "" {
+			return errors.New("config: Socket is required when Network is unix")
+		}
+	default:
+		return fmt.Errorf("config: unsupported network %q (supported: %s, %s)", c.Network, NetworkTCP, NetworkUnix)
+	}
+	switch {
+	case c.User == "":
+		return errors.New("config: User is required")
+	case c.Database == "":
+		return errors.New("config: Database is required")
+	}
+	return nil
+}
+
+// DSN renders c as a connection string for its dialect.
+func (c Config) DSN() string {
+	if c.dialect() == DialectPostgres {
+		return c.postgresDSN()
+	}
+	return c.mysqlDSN()
+}
+
+func (c Config) mysqlDSN() string {
+	var b strings.Builder
+	b.WriteString(c.User)
+	if c.Password != "" {
+		b.WriteString(":")
+		b.WriteString(c.Password)
+	}
+	if c.Network == NetworkUnix {
+		fmt.Fprintf(&b, "@unix(%s)/%s", c.Socket, c.Database)
+	} else {
+		port := c.Port
+		if port == 0 {
+			port = defaultPort
+		}
+		fmt.Fprintf(&b, "@tcp(%s)/%s", net.JoinHostPort(c.Host, strconv.Itoa(port)), c.Database)
+	}
+
+	var params []string
+	charset, collation := c.Charset, c.Collation
+	if charset == "" {
+		charset = defaultCharset
+	}
+	// Only default the collation for utf8mb4; pairing it with another
+	// charset would make the server reject the connection.
+	if collation == "" && charset == defaultCharset {
+		collation = defaultCollation
+	}
+	params = append(params, "charset="+url.QueryEscape(charset))
+	if collation != "" {
+		params = append(params, "collation="+url.QueryEscape(collation))
+	}
+	if c.ParseTime {
+		params = append(params, "parseTime="+strconv.FormatBool(c.ParseTime))
+	}
+	if c.Loc != "" {
+		params = append(params, "loc="+url.QueryEscape(c.Loc))
+	}
+	if c.TLS != nil {
+		params = append(params, "tls="+c.TLS.key())
+	}
+	if c.Timeout > 0 {
+		params = append(params, "timeout="+c.Timeout.String())
+	}
+	if c.ReadTimeout > 0 {
+		params = append(params, "readTimeout="+c.ReadTimeout.String())
+	}
+	if c.WriteTimeout > 0 {
+		params = append(params, "writeTimeout="+c.WriteTimeout.String())
+	}
+	params = append(params, "connectionAttributes="+url.QueryEscape("program_name:"+c.appName()))
+	for _, k := range sortedKeys(c.Params) {
+		params = append(params, url.QueryEscape(k)+"="+url.QueryEscape(c.Params[k]))
+	}
+	if len(params) > 0 {
+		b.WriteString("?")
+		b.WriteString(strings.Join(params, "&"))
+	}
+	return b.String()
+}
+
+func (c Config) postgresDSN() string {
+	port := c.Port
+	if port == 0 {
+		port = defaultPostgresPort
+	}
+	kv := []string{
+		"host=" + pqQuote(c.Host),
+		"port=" + strconv.Itoa(port),
+		"user=" + pqQuote(c.User),
+	}
+	if c.Password != "" {
+		kv = append(kv, "password="+pqQuote(c.Password))
+	}
+	kv = append(kv, "dbname="+pqQuote(c.Database))
+	for _, k := range sortedKeys(c.Params) {
+		kv = append(kv, k+"="+pqQuote(c.Params[k]))
+	}
+	return strings.Join(kv, " ")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// pqQuote quotes v for a lib/pq key=value DSN when it contains characters
+// that would otherwise end the value.
+func pqQuote(v string) string {
+	if v != "" && !strings.ContainsAny(v, ` '\`) {
+		return v
+	}
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `'`, `\'`)
+	return "'" + v + "'"
+}
+
+// ConnectWithConfig validates cfg and opens the connection it describes with
+// the default pool settings. A TLS config is registered with the mysql
+// driver before connecting.
+func ConnectWithConfig(cfg Config) error {
+	if err := cfg.prepare(); err != nil {
+		return err
+	}
+	return open(cfg.dialect(), cfg.DSN(), PoolConfig{})
+}
+
+// prepare validates c and registers its TLS config with the mysql driver.
+func (c Config) prepare() error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	if c.TLS != nil && c.dialect() == DialectMySQL {
+		return c.TLS.register()
+	}
+	return nil
+}
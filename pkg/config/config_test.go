@@ -0,0 +1,191 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// defaultAttrs is the tail mysqlDSN always emits for AppName "svc".
+const defaultAttrs = "charset=utf8mb4&collation=utf8mb4_unicode_ci&connectionAttributes=program_name%3Asvc"
+
+func TestConfigDSN(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{
+			name: "minimal",
+			cfg:  Config{User: "u", Host: "db", Database: "shop", AppName: "svc"},
+			want: "u@tcp(db:3306)/shop?" + defaultAttrs,
+		},
+		{
+			name: "password and port",
+			cfg:  Config{User: "u", Password: "p@ss", Host: "db", Port: 3307, Database: "shop", AppName: "svc"},
+			want: "u:p@ss@tcp(db:3307)/shop?" + defaultAttrs,
+		},
+		{
+			name: "ipv6 host",
+			cfg:  Config{User: "u", Host: "::1", Database: "shop", AppName: "svc"},
+			want: "u@tcp([::1]:3306)/shop?" + defaultAttrs,
+		},
+		{
+			name: "parseTime and loc",
+			cfg:  Config{User: "u", Host: "db", Database: "shop", ParseTime: true, Loc: "Europe/Paris", AppName: "svc"},
+			want: "u@tcp(db:3306)/shop?charset=utf8mb4&collation=utf8mb4_unicode_ci&parseTime=true&loc=Europe%2FParis&connectionAttributes=program_name%3Asvc",
+		},
+		{
+			name: "extra params in key order",
+			cfg:  Config{User: "u", Host: "db", Database: "shop", AppName: "svc", Params: map[string]string{"maxAllowedPacket": "0", "interpolateParams": "true"}},
+			want: "u@tcp(db:3306)/shop?" + defaultAttrs + "&interpolateParams=true&maxAllowedPacket=0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.DSN(); got != tt.want {
+				t.Errorf("DSN() =\n  %s\nwant\n  %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigCharset(t *testing.T) {
+	tests := []struct {
+		name               string
+		charset, collation string
+		want               string
+	}{
+		{"defaults", "", "", "charset=utf8mb4&collation=utf8mb4_unicode_ci"},
+		{"custom collation", "", "utf8mb4_0900_ai_ci", "charset=utf8mb4&collation=utf8mb4_0900_ai_ci"},
+		{"custom charset", "latin1", "", "charset=latin1&connectionAttributes"},
+		{"custom charset and collation", "latin1", "latin1_swedish_ci", "charset=latin1&collation=latin1_swedish_ci"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Config{User: "u", Host: "db", Database: "shop", Charset: tt.charset, Collation: tt.collation}
+			if got := c.DSN(); !strings.Contains(got, "?"+tt.want) {
+				t.Errorf("DSN() = %s, want params starting %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigAppName(t *testing.T) {
+	tests := []struct {
+		name    string
+		appName string
+		want    string
+	}{
+		{"explicit", "orders-api", "program_name%3Aorders-api"},
+		// Tests run as <package>.test.
+		{"binary name by default", "", "program_name%3Aconfig.test"},
+		{"separators replaced", "a,b:c", "program_name%3Aa_b_c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Config{User: "u", Host: "db", Database: "shop", AppName: tt.appName}
+			if got := c.DSN(); !strings.HasSuffix(got, "connectionAttributes="+tt.want) {
+				t.Errorf("DSN() = %s, want connectionAttributes=%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigNetwork(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{"tcp by default", Config{User: "u", Host: "db", Database: "shop", AppName: "svc"}, "u@tcp(db:3306)/shop?"},
+		{"explicit tcp", Config{User: "u", Host: "db", Port: 3307, Network: NetworkTCP, Database: "shop", AppName: "svc"}, "u@tcp(db:3307)/shop?"},
+		{"unix socket", Config{User: "u", Password: "p", Network: NetworkUnix, Socket: "/run/mysqld/mysqld.sock", Database: "shop", AppName: "svc"}, "u:p@unix(/run/mysqld/mysqld.sock)/shop?"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if got := tt.cfg.DSN(); got != tt.want+defaultAttrs {
+				t.Errorf("DSN() = %s, want %s", got, tt.want+defaultAttrs)
+			}
+		})
+	}
+}
+
+func TestConfigTimeouts(t *testing.T) {
+	cfg := Config{User: "u", Host: "db", Database: "shop", AppName: "svc", Timeout: 10 * time.Second, ReadTimeout: 5 * time.Second}
+	dsn := cfg.DSN()
+	for _, want := range []string{"timeout=10s", "readTimeout=5s"} {
+		if !strings.Contains(dsn, want) {
+			t.Errorf("DSN() = %s, want it to contain %s", dsn, want)
+		}
+	}
+	if strings.Contains(dsn, "writeTimeout") {
+		t.Errorf("DSN() = %s, want no writeTimeout for a zero WriteTimeout", dsn)
+	}
+}
+
+func TestConfigPostgresDSN(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{
+			name: "default port",
+			cfg:  Config{Dialect: DialectPostgres, User: "u", Password: "p", Host: "db", Database: "shop"},
+			want: "host=db port=5432 user=u password=p dbname=shop",
+		},
+		{
+			name: "quoted values",
+			cfg:  Config{Dialect: DialectPostgres, User: "u", Password: `it's a \secret`, Host: "db", Port: 5433, Database: "shop"},
+			want: `host=db port=5433 user=u password='it\'s a \\secret' dbname=shop`,
+		},
+		{
+			name: "params",
+			cfg:  Config{Dialect: DialectPostgres, User: "u", Host: "db", Database: "shop", Params: map[string]string{"sslmode": "disable", "application_name": "svc"}},
+			want: "host=db port=5432 user=u dbname=shop application_name=svc sslmode=disable",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.DSN(); got != tt.want {
+				t.Errorf("DSN() =\n  %s\nwant\n  %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	valid := Config{User: "u", Host: "db", Database: "shop"}
+	tests := []struct {
+		name    string
+		mutate  func(*Config)
+		wantErr string
+	}{
+		{"valid", func(*Config) {}, ""},
+		{"missing user", func(c *Config) { c.User = "" }, "User is required"},
+		{"missing host", func(c *Config) { c.Host = "" }, "Host is required"},
+		{"missing database", func(c *Config) { c.Database = "" }, "Database is required"},
+		{"postgres", func(c *Config) { c.Dialect = DialectPostgres }, ""},
+		{"unix without socket", func(c *Config) { c.Network = NetworkUnix }, "Socket is required"},
+		{"unix needs no host", func(c *Config) { c.Network, c.Socket, c.Host = NetworkUnix, "/tmp/mysql.sock", "" }, ""},
+		{"unsupported network", func(c *Config) { c.Network = "udp" }, `unsupported network "udp"`},
+		{"unsupported dialect", func(c *Config) { c.Dialect = "oracle" }, `unsupported dialect "oracle" (supported: mysql, postgres)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.mutate(&cfg)
+			err := cfg.Validate()
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("Validate() = %v, want nil", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("Validate() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
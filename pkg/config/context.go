@@ -0,0 +1,203 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"time"
+	"unsafe"
+
+	"github.com/jinzhu/gorm"
+)
+
+// contextKey is the gorm setting under which GetDBContext stores the
+// request context.
+const contextKey = "config:context"
+
+// GetDBContext returns a session of the package connection whose
+// statements run with ctx, so the driver aborts them, including a query
+// already running on the server, once ctx is done. Writes that GORM wraps
+// in its own transaction (Create, Save, Update, Delete) run in a
+// transaction bound to ctx instead, which is rolled back when ctx ends. As
+// with a transaction, calling DB on the session panics; use SQLDB.
+//
+// The session is opened over the same pool with the package's logging and
+// naming settings; settings made directly on GetBD do not carry over. A
+// nil or background ctx returns the plain connection.
+func GetDBContext(ctx context.Context) *gorm.DB {
+	d := GetBD()
+	if d == nil || ctx == nil || ctx == context.Background() {
+		return d
+	}
+	sqlDB, ok := d.CommonDB().(*sql.DB)
+	if !ok {
+		return d.Set(contextKey, ctx)
+	}
+	s, err := gorm.Open(d.Dialect().GetName(), &ctxDB{ctxConn{ctx, sqlDB}, sqlDB})
+	if err != nil {
+		// Only a string source can make Open fail.
+		return d.Set(contextKey, ctx)
+	}
+	mu.RLock()
+	applySettings(s)
+	mu.RUnlock()
+	return s.Set(contextKey, ctx)
+}
+
+// contextFrom returns the context attached to scope by GetDBContext.
+func contextFrom(scope *gorm.Scope) (context.Context, bool) {
+	v, ok := scope.Get(contextKey)
+	if !ok {
+		return nil, false
+	}
+	ctx, ok := v.(context.Context)
+	return ctx, ok
+}
+
+// WithTimeout is a scope that runs the statement with a deadline of d from
+// now, derived from any context attached with GetDBContext or UseSchema.
+// The deadline is enforced by the driver the same way as for GetDBContext.
+// It is a no-op when d <= 0.
+//
+// A scope is applied with conditions already chained onto the session, and
+// GORM v1 cannot swap a session's connection without dropping them, so the
+// connection field is rewritten in place (see setConn). Should a future
+// gorm lay out DB differently, the deadline is still attached to the
+// session but no longer enforced by the driver.
+func WithTimeout(d time.Duration) func(*gorm.DB) *gorm.DB {
+	return func(s *gorm.DB) *gorm.DB {
+		if d <= 0 {
+			return s
+		}
+		parent := context.Background()
+		if v, ok := s.Get(contextKey); ok {
+			if c, ok := v.(context.Context); ok {
+				parent = c
+			}
+		}
+		ctx, cancel := context.WithTimeout(parent, d)
+		// The statement runs after the scope returns, so release the
+		// context's resources once it is done instead of here.
+		context.AfterFunc(ctx, cancel)
+		return bindContext(s, ctx)
+	}
+}
+
+// bindContext returns a copy of s carrying ctx whose statements run with
+// ctx on the connection of s, when setConn is supported.
+func bindContext(s *gorm.DB, ctx context.Context) *gorm.DB {
+	s = s.Set(contextKey, ctx)
+	if connOffset < 0 {
+		return s
+	}
+	var bound gorm.SQLCommon
+	switch c := unwrapConn(s.CommonDB()).(type) {
+	case *sql.Tx:
+		bound = &ctxTx{ctxConn{ctx, c}, c}
+	case beginner:
+		bound = &ctxDB{ctxConn{ctx, c}, c}
+	default:
+		return s
+	}
+	setConn(s, bound)
+	return s
+}
+
+// unwrapConn returns the database/sql handle behind a connection installed
+// by bindContext or UseSchema.
+func unwrapConn(c gorm.SQLCommon) interface{} {
+	switch c := c.(type) {
+	case *ctxDB:
+		return c.db
+	case *ctxTx:
+		return c.tx
+	case *pinnedConn:
+		return c.db
+	}
+	return c
+}
+
+// connOffset is the offset of the unexported gorm.DB field holding the
+// connection a session runs its statements on, or -1 if this gorm version
+// has no such field.
+var connOffset = func() int {
+	f, ok := reflect.TypeOf((*gorm.DB)(nil)).Elem().FieldByName("db")
+	if !ok || f.Type != reflect.TypeOf((*gorm.SQLCommon)(nil)).Elem() {
+		return -1
+	}
+	return int(f.Offset)
+}()
+
+// setConn points the session s, which must not be shared yet, at c. GORM
+// v1 only changes a session's connection in Open and BeginTx, neither of
+// which keeps the conditions already chained onto s.
+func setConn(s *gorm.DB, c gorm.SQLCommon) {
+	*(*gorm.SQLCommon)(unsafe.Add(unsafe.Pointer(s), connOffset)) = c
+	s.Dialect().SetDB(c)
+}
+
+// runner is implemented by *sql.DB, *sql.Conn and *sql.Tx.
+type runner interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// beginner is implemented by *sql.DB and *sql.Conn.
+type beginner interface {
+	runner
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// ctxConn runs every statement with ctx.
+type ctxConn struct {
+	ctx  context.Context
+	conn runner
+}
+
+func (c ctxConn) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return c.conn.ExecContext(c.ctx, query, args...)
+}
+
+func (c ctxConn) Prepare(query string) (*sql.Stmt, error) {
+	return c.conn.PrepareContext(c.ctx, query)
+}
+
+func (c ctxConn) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return c.conn.QueryContext(c.ctx, query, args...)
+}
+
+func (c ctxConn) QueryRow(query string, args ...interface{}) *sql.Row {
+	return c.conn.QueryRowContext(c.ctx, query, args...)
+}
+
+// ctxDB is a ctxConn over a *sql.DB or *sql.Conn whose transactions are
+// bound to ctx.
+type ctxDB struct {
+	ctxConn
+	db beginner
+}
+
+func (c *ctxDB) Begin() (*sql.Tx, error) {
+	return c.db.BeginTx(c.ctx, nil)
+}
+
+func (c *ctxDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return c.db.BeginTx(ctx, opts)
+}
+
+// ctxTx is a ctxConn over a transaction, which the session can still
+// commit or roll back.
+type ctxTx struct {
+	ctxConn
+	tx *sql.Tx
+}
+
+func (c *ctxTx) Commit() error {
+	return c.tx.Commit()
+}
+
+func (c *ctxTx) Rollback() error {
+	return c.tx.Rollback()
+}
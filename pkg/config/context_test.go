@@ -0,0 +1,165 @@
+//go:build sqlite
+
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// slowQuery counts to 1e9 in SQLite, which takes far longer than any
+// deadline used in these tests.
+const slowQuery = "WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c WHERE x < 1000000000) SELECT count(*) FROM c"
+
+type ctxItem struct {
+	ID   uint
+	Name string
+}
+
+func TestGetDBContextBackground(t *testing.T) {
+	d := connectTest(t)
+	if got := GetDBContext(context.Background()); got != d {
+		t.Errorf("GetDBContext(Background) = %p, want the package connection %p", got, d)
+	}
+}
+
+func TestGetDBContextCancelled(t *testing.T) {
+	connectTest(t, &ctxItem{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var items []ctxItem
+	if err := GetDBContext(ctx).Find(&items).Error; !errors.Is(err, context.Canceled) {
+		t.Errorf("Find = %v, want context.Canceled", err)
+	}
+	if err := GetDBContext(ctx).Create(&ctxItem{Name: "late"}).Error; !errors.Is(err, context.Canceled) {
+		t.Errorf("Create = %v, want context.Canceled", err)
+	}
+}
+
+func TestGetDBContextAbortsRunningQuery(t *testing.T) {
+	connectTest(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	var n int64
+	err := GetDBContext(ctx).Raw(slowQuery).Row().Scan(&n)
+	if err == nil {
+		t.Fatal("slow query completed, want it cancelled")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("query returned after %s, want it aborted soon after the cancel", elapsed)
+	}
+}
+
+func TestGetDBContextSession(t *testing.T) {
+	d := connectTest(t, &ctxItem{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := GetDBContext(ctx)
+	for _, name := range []string{"a", "b"} {
+		if err := s.Create(&ctxItem{Name: name}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	var got ctxItem
+	if err := s.Where("name = ?", "b").First(&got).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "b" {
+		t.Errorf("First = %+v, want the row named b", got)
+	}
+	if n := countRows(t, d, &ctxItem{}); n != 2 {
+		t.Errorf("%d rows, want 2", n)
+	}
+}
+
+func TestWithTimeoutAbortsSlowQuery(t *testing.T) {
+	d := connectTest(t)
+	start := time.Now()
+	var n int64
+	err := d.Scopes(WithTimeout(50 * time.Millisecond)).Raw(slowQuery).Row().Scan(&n)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("slow query = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("query returned after %s, want it aborted near the 50ms deadline", elapsed)
+	}
+}
+
+func TestWithTimeoutKeepsChain(t *testing.T) {
+	d := connectTest(t, &ctxItem{})
+	for _, name := range []string{"a", "b"} {
+		d.Create(&ctxItem{Name: name})
+	}
+
+	var items []ctxItem
+	err := d.Where("name = ?", "b").Scopes(WithTimeout(time.Second)).Find(&items).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Name != "b" {
+		t.Errorf("Find = %+v, want only the row named b", items)
+	}
+}
+
+func TestWithTimeoutInheritsContext(t *testing.T) {
+	connectTest(t, &ctxItem{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var items []ctxItem
+	err := GetDBContext(ctx).Scopes(WithTimeout(time.Minute)).Find(&items).Error
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Find = %v, want the parent's context.Canceled", err)
+	}
+}
+
+func TestWithTimeoutNonPositive(t *testing.T) {
+	d := connectTest(t)
+	for _, timeout := range []time.Duration{0, -time.Second} {
+		s := d.Scopes(WithTimeout(timeout))
+		if s.CommonDB() != d.CommonDB() {
+			t.Errorf("WithTimeout(%s) rebound the connection, want a no-op", timeout)
+		}
+		if _, ok := s.Get(contextKey); ok {
+			t.Errorf("WithTimeout(%s) attached a context, want a no-op", timeout)
+		}
+	}
+}
+
+func TestGetDBContextSettings(t *testing.T) {
+	connectTest(t)
+	l := useLogger(t)
+	SetLogMode(true)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	GetDBContext(ctx).Exec("SELECT 1")
+	if !l.logged("SELECT 1") {
+		t.Error("session from GetDBContext did not pick up the package log settings")
+	}
+}
+
+// TestWithTimeoutUnsupportedLayout pretends gorm.DB has no connection
+// field: the deadline is still attached but not enforced.
+func TestWithTimeoutUnsupportedLayout(t *testing.T) {
+	d := connectTest(t)
+	saved := connOffset
+	connOffset = -1
+	t.Cleanup(func() { connOffset = saved })
+
+	s := d.Scopes(WithTimeout(time.Minute))
+	if _, ok := s.Get(contextKey); !ok {
+		t.Error("WithTimeout did not attach its context")
+	}
+	if s.CommonDB() != d.CommonDB() {
+		t.Error("WithTimeout rebound the connection without a known layout")
+	}
+	if err := s.Exec("SELECT 1").Error; err != nil {
+		t.Errorf("Exec = %v", err)
+	}
+}
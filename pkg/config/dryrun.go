@@ -0,0 +1,187 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// DryRun runs fn against a session whose statements are recorded instead of
+// being sent to the database, and returns them with their parameters
+// interpolated, one statement per line. The session uses the dialect of the
+// package connection (mysql if not connected). Queries see no rows, so
+// follow-up statements that depend on results may differ from a real run.
+func DryRun(fn func(tx *gorm.DB)) (string, error) {
+	dialect := DialectMySQL
+	if d := GetBD(); d != nil {
+		dialect = d.Dialect().GetName()
+	}
+
+	rec := &recorder{dialect: dialect}
+	sqlDB := sql.OpenDB(rec)
+	defer sqlDB.Close()
+	sqlDB.SetMaxOpenConns(1)
+
+	d, err := gorm.Open(dialect, sqlDB)
+	if err != nil {
+		return "", fmt.Errorf("config: opening dry-run session: %w", err)
+	}
+	fn(d.LogMode(false))
+	return strings.Join(rec.statements(), ";\n"), nil
+}
+
+// recorder is a database/sql connector that records every statement it is
+// asked to run and returns empty results.
+type recorder struct {
+	dialect string
+
+	mu    sync.Mutex
+	stmts []string
+}
+
+func (r *recorder) Connect(context.Context) (driver.Conn, error) { return (*recordingConn)(r), nil }
+func (r *recorder) Driver() driver.Driver                        { return recordingDriver{r} }
+
+func (r *recorder) record(query string, args []driver.NamedValue) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stmts = append(r.stmts, interpolate(r.dialect, query, args))
+}
+
+func (r *recorder) statements() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.stmts...)
+}
+
+type recordingDriver struct{ r *recorder }
+
+func (d recordingDriver) Open(string) (driver.Conn, error) { return (*recordingConn)(d.r), nil }
+
+type recordingConn recorder
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return &recordingStmt{c: c, query: query}, nil
+}
+func (c *recordingConn) Close() error              { return nil }
+func (c *recordingConn) Begin() (driver.Tx, error) { return recordingTx{}, nil }
+
+func (c *recordingConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	(*recorder)(c).record(query, args)
+	return recordingResult{}, nil
+}
+
+func (c *recordingConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	(*recorder)(c).record(query, args)
+	return emptyRows{}, nil
+}
+
+type recordingStmt struct {
+	c     *recordingConn
+	query string
+}
+
+func (s *recordingStmt) Close() error  { return nil }
+func (s *recordingStmt) NumInput() int { return -1 }
+
+func (s *recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.c.ExecContext(context.Background(), s.query, namedValues(args))
+}
+
+func (s *recordingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.c.QueryContext(context.Background(), s.query, namedValues(args))
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	nv := make([]driver.NamedValue, len(args))
+	for i, a := range args {
+		nv[i] = driver.NamedValue{Ordinal: i + 1, Value: a}
+	}
+	return nv
+}
+
+type recordingTx struct{}
+
+func (recordingTx) Commit() error   { return nil }
+func (recordingTx) Rollback() error { return nil }
+
+type recordingResult struct{}
+
+func (recordingResult) LastInsertId() (int64, error) { return 0, nil }
+func (recordingResult) RowsAffected() (int64, error) { return 0, nil }
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string         { return nil }
+func (emptyRows) Close() error              { return nil }
+func (emptyRows) Next([]driver.Value) error { return io.EOF }
+
+var numberedPlaceholderRe = regexp.MustCompile(`\$\d+`)
+
+// interpolate substitutes args into query for display. Placeholders are
+// "$n" for postgres and "?" otherwise; "?" inside quoted literals is kept.
+func interpolate(dialect, query string, args []driver.NamedValue) string {
+	if len(args) == 0 {
+		return query
+	}
+	if dialect == DialectPostgres {
+		return numberedPlaceholderRe.ReplaceAllStringFunc(query, func(p string) string {
+			n, _ := strconv.Atoi(p[1:])
+			if n < 1 || n > len(args) {
+				return p
+			}
+			return literal(args[n-1].Value)
+		})
+	}
+
+	var (
+		b     strings.Builder
+		next  int
+		quote rune
+	)
+	for _, ch := range query {
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+		case ch == '?' && next < len(args):
+			b.WriteString(literal(args[next].Value))
+			next++
+			continue
+		}
+		b.WriteRune(ch)
+	}
+	return b.String()
+}
+
+func literal(v driver.Value) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	case []byte:
+		return "'" + strings.ReplaceAll(string(v), "'", "''") + "'"
+	case time.Time:
+		return "'" + v.Format("2006-01-02 15:04:05.999999") + "'"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	default:
+		return fmt.Sprint(v)
+	}
+}
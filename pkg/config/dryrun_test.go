@@ -0,0 +1,66 @@
+package config
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+func TestDryRunWhere(t *testing.T) {
+	sql, err := DryRun(func(tx *gorm.DB) {
+		tx.Where("name = ? AND id > ?", "O'Brien", 3).Find(&[]pageItem{})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "WHERE (name = 'O''Brien' AND id > 3)"
+	if !strings.Contains(sql, want) {
+		t.Errorf("DryRun() = %q, want it to contain %q", sql, want)
+	}
+}
+
+func TestDryRunStatements(t *testing.T) {
+	sql, err := DryRun(func(tx *gorm.DB) {
+		tx.Exec("DELETE FROM page_items WHERE id = ?", 1)
+		tx.Exec("DELETE FROM page_items WHERE id = ?", 2)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "DELETE FROM page_items WHERE id = 1;\nDELETE FROM page_items WHERE id = 2"
+	if sql != want {
+		t.Errorf("DryRun() = %q, want %q", sql, want)
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		dialect string
+		query   string
+		args    []interface{}
+		want    string
+	}{
+		{"no args", DialectMySQL, "SELECT 1", nil, "SELECT 1"},
+		{"mysql", DialectMySQL, "a = ? AND b = ?", []interface{}{"x", int64(2)}, "a = 'x' AND b = 2"},
+		{"quoted placeholder", DialectMySQL, "a = '?' AND b = ?", []interface{}{true}, "a = '?' AND b = TRUE"},
+		{"null and time", DialectMySQL, "a = ? AND b = ?", []interface{}{nil, at}, "a = NULL AND b = '2024-03-01 12:30:00'"},
+		{"postgres", DialectPostgres, "a = $2 AND b = $1", []interface{}{"x", []byte("y")}, "a = 'y' AND b = 'x'"},
+		{"postgres out of range", DialectPostgres, "a = $3", []interface{}{1}, "a = $3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := make([]driver.NamedValue, len(tt.args))
+			for i, a := range tt.args {
+				args[i] = driver.NamedValue{Ordinal: i + 1, Value: a}
+			}
+			if got := interpolate(tt.dialect, tt.query, args); got != tt.want {
+				t.Errorf("interpolate(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+const (
+	defaultPort   = 3306
+	defaultParams = "charset=utf8&parseTime=True&loc=Local"
+)
+
+// BuildDSN assembles the MySQL DSN from DB_USER, DB_PASSWORD, DB_HOST,
+// DB_PORT, DB_NAME and DB_PARAMS. DB_USER, DB_HOST and DB_NAME are required.
+func BuildDSN() (string, error) {
+	user, err := requireEnv("DB_USER")
+	if err != nil {
+		return "", err
+	}
+	host, err := requireEnv("DB_HOST")
+	if err != nil {
+		return "", err
+	}
+	name, err := requireEnv("DB_NAME")
+	if err != nil {
+		return "", err
+	}
+	port := envOr("DB_PORT", strconv.Itoa(defaultPort))
+	params := envOr("DB_PARAMS", defaultParams)
+
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?%s", user, os.Getenv("DB_PASSWORD"), host, port, name, params), nil
+}
+
+func requireEnv(key string) (string, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return "", fmt.Errorf("config: missing required environment variable %s", key)
+	}
+	return v, nil
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
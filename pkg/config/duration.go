@@ -0,0 +1,57 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// duration is a time.Duration that JSON may spell as a string such as
+// "10s" or as integer nanoseconds.
+type duration time.Duration
+
+func (d *duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		var n int64
+		if err := json.Unmarshal(b, &n); err != nil {
+			return fmt.Errorf("config: invalid duration %s", b)
+		}
+		*d = duration(n)
+		return nil
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("config: invalid duration %q", s)
+	}
+	*d = duration(v)
+	return nil
+}
+
+// plainConfig is Config without its methods, so decoding into it does not
+// recurse into Config.UnmarshalJSON.
+type plainConfig Config
+
+// jsonConfig decodes into a Config, reading the timeouts as durations.
+// Its fields shadow the embedded ones with the same JSON names.
+type jsonConfig struct {
+	*plainConfig
+	Timeout      *duration `json:"timeout"`
+	ReadTimeout  *duration `json:"readTimeout"`
+	WriteTimeout *duration `json:"writeTimeout"`
+}
+
+func newJSONConfig(c *Config) *jsonConfig {
+	return &jsonConfig{
+		plainConfig:  (*plainConfig)(c),
+		Timeout:      (*duration)(&c.Timeout),
+		ReadTimeout:  (*duration)(&c.ReadTimeout),
+		WriteTimeout: (*duration)(&c.WriteTimeout),
+	}
+}
+
+// UnmarshalJSON decodes c like encoding/json would, except that the
+// timeouts may also be duration strings such as "10s".
+func (c *Config) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, newJSONConfig(c))
+}
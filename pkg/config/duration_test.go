@@ -0,0 +1,32 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigUnmarshalJSON(t *testing.T) {
+	var wrapper struct {
+		DB    Config `json:"db"`
+		Other string `json:"other"`
+	}
+	in := `{"db":{"host":"db","port":3307,"timeout":"10s","readTimeout":2000000000,"extra":1},"other":"x"}`
+	if err := json.Unmarshal([]byte(in), &wrapper); err != nil {
+		t.Fatal(err)
+	}
+	got := wrapper.DB
+	if got.Host != "db" || got.Port != 3307 || got.Timeout != 10*time.Second || got.ReadTimeout != 2*time.Second || got.WriteTimeout != 0 {
+		t.Errorf("Unmarshal = %+v, want host, port and both timeouts set", got)
+	}
+	if wrapper.Other != "x" {
+		t.Errorf("sibling field = %q, want x", wrapper.Other)
+	}
+
+	var cfg Config
+	err := json.Unmarshal([]byte(`{"writeTimeout":true}`), &cfg)
+	if err == nil || !strings.Contains(err.Error(), "invalid duration true") {
+		t.Errorf("Unmarshal = %v, want an invalid duration error", err)
+	}
+}
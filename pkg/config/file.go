@@ -0,0 +1,50 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadConfig reads a Config from a .json, .yaml or .yml file. Unknown keys
+// are rejected so typos in mounted secrets surface early.
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("config: reading %s: %w", path, err)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		// Decode the shadow directly: DisallowUnknownFields does not reach
+		// into Config.UnmarshalJSON.
+		err = dec.Decode(newJSONConfig(&cfg))
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&cfg)
+	default:
+		return cfg, fmt.Errorf("config: unsupported config file extension %q (want .json, .yaml or .yml)", ext)
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("config: parsing %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// ConnectFromFile loads the Config at path and connects with it.
+func ConnectFromFile(path string) error {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	return ConnectWithConfig(cfg)
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	want := Config{User: "u", Password: "p", Host: "db", Port: 3307, Database: "shop", Params: map[string]string{"interpolateParams": "true"}}
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"json", "db.json", `{"user":"u","password":"p","host":"db","port":3307,"database":"shop","params":{"interpolateParams":"true"}}`},
+		{"yaml", "db.yaml", "user: u\npassword: p\nhost: db\nport: 3307\ndatabase: shop\nparams:\n  interpolateParams: \"true\"\n"},
+		{"yml", "db.YML", "user: u\npassword: p\nhost: db\nport: 3307\ndatabase: shop\nparams:\n  interpolateParams: \"true\"\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadConfig(writeFile(t, tt.file, tt.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("LoadConfig() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigDurations(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"json strings", "db.json", `{"timeout":"10s","readTimeout":"1m30s","writeTimeout":"500ms"}`},
+		{"json nanoseconds", "db.json", `{"timeout":10000000000,"readTimeout":90000000000,"writeTimeout":500000000}`},
+		{"yaml strings", "db.yaml", "timeout: 10s\nreadTimeout: 1m30s\nwriteTimeout: 500ms\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadConfig(writeFile(t, tt.file, tt.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Timeout != 10*time.Second || got.ReadTimeout != 90*time.Second || got.WriteTimeout != 500*time.Millisecond {
+				t.Errorf("timeouts = %s, %s, %s; want 10s, 1m30s, 500ms", got.Timeout, got.ReadTimeout, got.WriteTimeout)
+			}
+		})
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr string
+	}{
+		{"unknown extension", "db.toml", `user = "u"`, `unsupported config file extension ".toml"`},
+		{"unknown json field", "db.json", `{"user":"u","hostname":"db"}`, `unknown field "hostname"`},
+		{"unknown yaml field", "db.yaml", "user: u\nhostname: db\n", "field hostname not found"},
+		{"bad json duration", "db.json", `{"timeout":"soon"}`, `invalid duration "soon"`},
+		{"yaml integer duration", "db.yaml", "timeout: 10\n", "cannot unmarshal !!int `10` into time.Duration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(writeFile(t, tt.file, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadConfig() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// HealthHandler reports the result of Ping: 200 with {"status":"ok"}, or
+// 503 with {"status":"unavailable","error":...} when the database is
+// unreachable or not connected.
+func HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := Ping(r.Context()); err != nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "unavailable", "error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+}
+
+// StatsHandler responds with the pool statistics from Stats as JSON, or 503
+// when the database is not connected.
+func StatsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stats, err := Stats()
+		if err != nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, stats)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	res, _ := json.Marshal(v)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(res)
+}
@@ -0,0 +1,97 @@
+//go:build sqlite
+
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// serve runs h on a GET request and returns the response, checking it is
+// JSON.
+func serve(t *testing.T, h http.Handler) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	return rec
+}
+
+func TestHealthHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(t *testing.T)
+		wantStatus int
+		wantField  string
+		wantError  string
+	}{
+		{
+			name:       "not connected",
+			setup:      func(*testing.T) {},
+			wantStatus: http.StatusServiceUnavailable,
+			wantField:  "unavailable",
+			wantError:  ErrNotConnected.Error(),
+		},
+		{
+			name:       "healthy",
+			setup:      func(t *testing.T) { connectTest(t) },
+			wantStatus: http.StatusOK,
+			wantField:  "ok",
+		},
+		{
+			name:       "unhealthy",
+			setup:      func(t *testing.T) { connectTest(t).DB().Close() },
+			wantStatus: http.StatusServiceUnavailable,
+			wantField:  "unavailable",
+			wantError:  "database is closed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(t)
+			rec := serve(t, HealthHandler())
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			var body map[string]string
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decoding %q: %v", rec.Body, err)
+			}
+			if body["status"] != tt.wantField {
+				t.Errorf("status field = %q, want %q", body["status"], tt.wantField)
+			}
+			if tt.wantError == "" {
+				if _, ok := body["error"]; ok {
+					t.Errorf("body = %v, want no error", body)
+				}
+			} else if !strings.Contains(body["error"], tt.wantError) {
+				t.Errorf("error field = %q, want it to contain %q", body["error"], tt.wantError)
+			}
+		})
+	}
+}
+
+func TestStatsHandler(t *testing.T) {
+	rec := serve(t, StatsHandler())
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), ErrNotConnected.Error()) {
+		t.Errorf("not connected: %d %s, want 503 with ErrNotConnected", rec.Code, rec.Body)
+	}
+
+	connectTest(t)
+	rec = serve(t, StatsHandler())
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var stats struct{ MaxOpenConnections int }
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatal(err)
+	}
+	if stats.MaxOpenConnections != 1 {
+		t.Errorf("MaxOpenConnections = %d, want the test pool's 1", stats.MaxOpenConnections)
+	}
+}
@@ -0,0 +1,19 @@
+package config
+
+import (
+	"context"
+	"fmt"
+)
+
+// Ping checks that the database is reachable, bounded by ctx. It returns
+// ErrNotConnected if Connect has not succeeded.
+func Ping(ctx context.Context) error {
+	sqlDB, err := SQLDB()
+	if err != nil {
+		return err
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return fmt.Errorf("config: ping failed: %w", err)
+	}
+	return nil
+}
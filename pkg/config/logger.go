@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Logger receives GORM's log output, already formatted.
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+var (
+	logger  Logger
+	logMode *bool
+)
+
+// gormLogger adapts a Logger to the Print method GORM calls.
+type gormLogger struct {
+	l Logger
+}
+
+func (g gormLogger) Print(v ...interface{}) {
+	g.l.Printf("%s", strings.TrimSuffix(fmt.Sprintln(gorm.LogFormatter(v...)...), "\n"))
+}
+
+// SetLogger routes GORM's logs to l. It may be called before Connect, in
+// which case it is applied once the connection opens. Like GORM's own
+// setters it is meant to be called during startup.
+func SetLogger(l Logger) {
+	mu.Lock()
+	defer mu.Unlock()
+	logger = l
+	forEachConn(applyLogging)
+}
+
+// SetLogMode toggles GORM's query logging. It may be called before Connect,
+// in which case it is applied once the connection opens.
+func SetLogMode(enabled bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	logMode = &enabled
+	forEachConn(applyLogging)
+}
+
+// applyLogging must be called with mu held.
+func applyLogging(d *gorm.DB) {
+	if logger != nil {
+		d.SetLogger(gormLogger{logger})
+	}
+	if logMode != nil {
+		d.LogMode(*logMode)
+	}
+}
+
+// logf writes a message from this package to the configured Logger, or to
+// the standard logger when none is set.
+func logf(format string, args ...interface{}) {
+	mu.RLock()
+	l := logger
+	mu.RUnlock()
+	if l == nil {
+		log.Printf(format, args...)
+		return
+	}
+	l.Printf(format, args...)
+}
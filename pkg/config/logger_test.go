@@ -0,0 +1,91 @@
+//go:build sqlite
+
+package config
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordLogger is a Logger keeping every line it receives.
+type recordLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *recordLogger) Printf(format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func (l *recordLogger) reset() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lines := l.lines
+	l.lines = nil
+	return lines
+}
+
+// logged reports whether any line so far contains s.
+func (l *recordLogger) logged(s string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, line := range l.lines {
+		if strings.Contains(line, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// useLogger installs a recordLogger until t ends.
+func useLogger(t *testing.T) *recordLogger {
+	l := &recordLogger{}
+	SetLogger(l)
+	t.Cleanup(func() {
+		mu.Lock()
+		logger, logMode = nil, nil
+		mu.Unlock()
+	})
+	return l
+}
+
+func TestSetLogModeBeforeConnect(t *testing.T) {
+	l := useLogger(t)
+	SetLogMode(true)
+	d, err := ConnectForTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer Close()
+
+	d.Exec("SELECT 1")
+	if lines := l.reset(); len(lines) != 1 || !strings.Contains(lines[0], "SELECT 1") {
+		t.Errorf("logged %q with log mode on, want the statement", lines)
+	}
+
+	SetLogMode(false)
+	d.Exec("SELECT 2")
+	d.Exec("SELECT * FROM missing_table")
+	if lines := l.reset(); len(lines) != 0 {
+		t.Errorf("logged %q with log mode off, want nothing", lines)
+	}
+}
+
+func TestSetLoggerAfterConnect(t *testing.T) {
+	d, err := ConnectForTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer Close()
+
+	l := useLogger(t)
+	SetLogMode(true)
+	d.Exec("SELECT 1")
+	if lines := l.reset(); len(lines) != 1 || !strings.Contains(lines[0], "SELECT 1") {
+		t.Errorf("logged %q, want the statement routed to the new logger", lines)
+	}
+}
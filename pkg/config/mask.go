@@ -0,0 +1,39 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+)
+
+const maskedPassword = "****"
+
+var (
+	pqDSNRe      = regexp.MustCompile(`^\s*\w+\s*=`)
+	pqPasswordRe = regexp.MustCompile(`password\s*=\s*('(?:[^'\\]|\\.)*'|\S+)`)
+)
+
+// MaskDSN replaces the password in a MySQL or lib/pq key=value DSN with
+// "****" so it can be logged. DSNs without a password are returned as is.
+func MaskDSN(dsn string) string {
+	if pqDSNRe.MatchString(dsn) {
+		return pqPasswordRe.ReplaceAllString(dsn, "password="+maskedPassword)
+	}
+
+	// Split the way go-sql-driver/mysql does: the last '/' starts the
+	// database name, the last '@' before it ends the credentials, and the
+	// first ':' separates user from password. The password may therefore
+	// contain ':' or '@' without affecting the tcp(host:port) part.
+	slash := strings.LastIndex(dsn, "/")
+	if slash < 0 {
+		return dsn
+	}
+	at := strings.LastIndex(dsn[:slash], "@")
+	if at < 0 {
+		return dsn
+	}
+	colon := strings.Index(dsn[:at], ":")
+	if colon < 0 {
+		return dsn
+	}
+	return dsn[:colon+1] + maskedPassword + dsn[at:]
+}
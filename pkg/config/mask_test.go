@@ -0,0 +1,31 @@
+package config
+
+import "testing"
+
+func TestMaskDSN(t *testing.T) {
+	tests := []struct {
+		name string
+		dsn  string
+		want string
+	}{
+		{"mysql password", "u:secret@tcp(db:3306)/shop?charset=utf8", "u:****@tcp(db:3306)/shop?charset=utf8"},
+		{"mysql no password", "u@tcp(db:3306)/shop", "u@tcp(db:3306)/shop"},
+		{"mysql no credentials", "tcp(db:3306)/shop", "tcp(db:3306)/shop"},
+		{"mysql empty password", "u:@tcp(db:3306)/shop", "u:****@tcp(db:3306)/shop"},
+		{"mysql special characters", "u:p@ss:w/o#rd@tcp(db:3306)/shop", "u:****@tcp(db:3306)/shop"},
+		{"mysql ipv6 host", "u:secret@tcp([::1]:3306)/shop", "u:****@tcp([::1]:3306)/shop"},
+		{"mysql unix socket", "u:secret@unix(/var/run/mysqld/mysqld.sock)/shop", "u:****@unix(/var/run/mysqld/mysqld.sock)/shop"},
+		{"postgres password", "host=db port=5432 user=u password=secret dbname=shop", "host=db port=5432 user=u password=**** dbname=shop"},
+		{"postgres quoted password", `host=db user=u password='it\'s a secret' dbname=shop`, "host=db user=u password=**** dbname=shop"},
+		{"postgres password with @", "host=db user=u password=p@ss dbname=shop", "host=db user=u password=**** dbname=shop"},
+		{"postgres spaced password", "host=db user=u password = secret dbname=shop", "host=db user=u password=**** dbname=shop"},
+		{"postgres no password", "host=db port=5432 user=u dbname=shop", "host=db port=5432 user=u dbname=shop"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MaskDSN(tt.dsn); got != tt.want {
+				t.Errorf("MaskDSN(%q) = %q, want %q", tt.dsn, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"sync/atomic"
+
+	"github.com/jinzhu/gorm"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type queryMetrics struct {
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+}
+
+var metrics atomic.Pointer[queryMetrics]
+
+func init() {
+	cb := gorm.DefaultCallback
+	cb.Create().After("gorm:commit_or_rollback_transaction").Register("config:metrics", observe("create"))
+	cb.Query().After("gorm:after_query").Register("config:metrics", observe("query"))
+	cb.RowQuery().After("gorm:row_query").Register("config:metrics", observe("query"))
+	cb.Update().After("gorm:commit_or_rollback_transaction").Register("config:metrics", observe("update"))
+	cb.Delete().After("gorm:commit_or_rollback_transaction").Register("config:metrics", observe("delete"))
+}
+
+// EnableMetrics registers a query duration histogram and an error counter,
+// both labelled by operation, with reg and starts recording them for every
+// statement. Registering with the same registry twice returns
+// prometheus.AlreadyRegisteredError.
+func EnableMetrics(reg prometheus.Registerer) error {
+	m := &queryMetrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "gorm_query_duration_seconds",
+			Help:    "Duration of database statements issued through GORM.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"operation"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gorm_query_errors_total",
+			Help: "Database statements issued through GORM that returned an error.",
+		}, []string{"operation"}),
+	}
+	if err := reg.Register(m.duration); err != nil {
+		return err
+	}
+	if err := reg.Register(m.errors); err != nil {
+		reg.Unregister(m.duration)
+		return err
+	}
+	metrics.Store(m)
+	return nil
+}
+
+func observe(operation string) func(*gorm.Scope) {
+	return func(scope *gorm.Scope) {
+		m := metrics.Load()
+		if m == nil {
+			return
+		}
+		if d, ok := elapsed(scope); ok {
+			m.duration.WithLabelValues(operation).Observe(d.Seconds())
+		}
+		// A lookup that matches nothing is not a database failure.
+		if scope.HasError() && !gorm.IsRecordNotFoundError(scope.DB().Error) {
+			m.errors.WithLabelValues(operation).Inc()
+		}
+	}
+}
@@ -0,0 +1,70 @@
+//go:build sqlite
+
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metricItem struct {
+	ID   uint
+	Name string
+}
+
+// gathered returns the value recorded for operation in the metric family
+// name: the sample count of a histogram or the value of a counter.
+func gathered(t *testing.T, reg *prometheus.Registry, name, operation string) float64 {
+	t.Helper()
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range families {
+		if f.GetName() != name {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "operation" && l.GetValue() == operation {
+					if h := m.GetHistogram(); h != nil {
+						return float64(h.GetSampleCount())
+					}
+					return m.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func TestEnableMetrics(t *testing.T) {
+	d := connectTest(t, &metricItem{})
+	reg := prometheus.NewRegistry()
+	if err := EnableMetrics(reg); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { metrics.Store(nil) })
+
+	d.Create(&metricItem{Name: "a"})
+	d.Find(&[]metricItem{})
+	d.First(&metricItem{}, 42) // not found is not an error
+	d.Raw("SELECT * FROM missing_table").Scan(&[]metricItem{})
+
+	if n := gathered(t, reg, "gorm_query_duration_seconds", "create"); n != 1 {
+		t.Errorf("create histogram has %v samples, want 1", n)
+	}
+	if n := gathered(t, reg, "gorm_query_duration_seconds", "query"); n < 2 {
+		t.Errorf("query histogram has %v samples, want at least 2", n)
+	}
+	if n := gathered(t, reg, "gorm_query_errors_total", "query"); n != 1 {
+		t.Errorf("query error counter = %v, want 1", n)
+	}
+
+	var are prometheus.AlreadyRegisteredError
+	if err := EnableMetrics(reg); !errors.As(err, &are) {
+		t.Errorf("second EnableMetrics() = %v, want prometheus.AlreadyRegisteredError", err)
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Migration is a single schema change identified by a unique ID.
+type Migration struct {
+	ID string
+	Up func(tx *gorm.DB) error
+}
+
+type schemaMigration struct {
+	ID        string `gorm:"primary_key;size:255"`
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// RunMigrations applies migrations in order, skipping IDs already recorded
+// in the schema_migrations table. Each migration runs in its own
+// transaction together with its bookkeeping row. Note that MySQL commits DDL
+// implicitly, so only data changes are rolled back on failure there.
+func RunMigrations(migrations []Migration) error {
+	d := GetBD()
+	if d == nil {
+		return ErrNotConnected
+	}
+	if err := d.AutoMigrate(&schemaMigration{}).Error; err != nil {
+		return fmt.Errorf("config: creating schema_migrations: %w", err)
+	}
+
+	for _, m := range migrations {
+		var count int
+		if err := d.Model(&schemaMigration{}).Where("id = ?", m.ID).Count(&count).Error; err != nil {
+			return fmt.Errorf("config: checking migration %s: %w", m.ID, err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		err := Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{ID: m.ID, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("config: migration %s failed: %w", m.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+//go:build sqlite
+
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+)
+
+type migrateItem struct {
+	ID   uint
+	Name string
+}
+
+func TestRunMigrationsIdempotent(t *testing.T) {
+	d := connectTest(t)
+	runs := map[string]int{}
+	migrations := []Migration{
+		{ID: "001_create_items", Up: func(tx *gorm.DB) error {
+			runs["001_create_items"]++
+			return tx.CreateTable(&migrateItem{}).Error
+		}},
+		{ID: "002_seed_item", Up: func(tx *gorm.DB) error {
+			runs["002_seed_item"]++
+			return tx.Create(&migrateItem{Name: "first"}).Error
+		}},
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := RunMigrations(migrations); err != nil {
+			t.Fatalf("run %d: %v", i+1, err)
+		}
+	}
+	for _, m := range migrations {
+		if runs[m.ID] != 1 {
+			t.Errorf("migration %s ran %d times, want 1", m.ID, runs[m.ID])
+		}
+	}
+	if n := countRows(t, d, &migrateItem{}); n != 1 {
+		t.Errorf("%d items, want 1", n)
+	}
+	if n := countRows(t, d, &schemaMigration{}); n != 2 {
+		t.Errorf("%d recorded migrations, want 2", n)
+	}
+}
+
+func TestRunMigrationsFailure(t *testing.T) {
+	d := connectTest(t, &migrateItem{})
+	errBoom := errors.New("boom")
+	err := RunMigrations([]Migration{
+		{ID: "001_ok", Up: func(*gorm.DB) error { return nil }},
+		{ID: "002_broken", Up: func(tx *gorm.DB) error {
+			if err := tx.Create(&migrateItem{Name: "partial"}).Error; err != nil {
+				return err
+			}
+			return errBoom
+		}},
+		{ID: "003_never", Up: func(*gorm.DB) error {
+			t.Error("migration after a failure ran")
+			return nil
+		}},
+	})
+	if !errors.Is(err, errBoom) || !strings.Contains(err.Error(), "002_broken") {
+		t.Fatalf("RunMigrations() = %v, want the error of 002_broken", err)
+	}
+	if n := countRows(t, d, &migrateItem{}); n != 0 {
+		t.Errorf("%d items after the failed migration, want it rolled back", n)
+	}
+	if n := countRows(t, d, &schemaMigration{}); n != 1 {
+		t.Errorf("%d recorded migrations, want only 001_ok", n)
+	}
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// BaseModel holds the columns shared by our tables. Embed it in a model to
+// get an auto-increment ID, timestamps and soft delete: GORM sets DeletedAt
+// on Delete and hides those rows from queries unless Unscoped is used.
+type BaseModel struct {
+	ID        uint       `gorm:"primary_key" json:"id"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+	DeletedAt *time.Time `sql:"index" json:"deletedAt,omitempty"`
+}
+
+// NotDeleted is a scope excluding soft-deleted rows. GORM already does this
+// for models with a DeletedAt field; the scope is for raw tables and joins
+// where that does not apply. The column is qualified with the table (or its
+// alias) given to Model or Table, so apply the scope after those.
+func NotDeleted(d *gorm.DB) *gorm.DB {
+	scope := d.NewScope(d.Value)
+	table := scope.TableName()
+	if table == "" {
+		return d.Where("deleted_at IS NULL")
+	}
+	if f := strings.Fields(table); len(f) > 1 {
+		table = f[len(f)-1]
+	}
+	return d.Where(scope.Quote(table) + ".deleted_at IS NULL")
+}
+
+// Unscoped returns a session of the package connection that includes
+// soft-deleted rows.
+func Unscoped(ctx context.Context) *gorm.DB {
+	d := GetDBContext(ctx)
+	if d == nil {
+		return nil
+	}
+	return d.Unscoped()
+}
@@ -0,0 +1,85 @@
+//go:build sqlite
+
+package config
+
+import (
+	"context"
+	"testing"
+)
+
+type modelAuthor struct {
+	BaseModel
+	Name string
+}
+
+type modelBook struct {
+	BaseModel
+	Title    string
+	AuthorID uint
+}
+
+func TestSoftDelete(t *testing.T) {
+	d := connectTest(t, &modelBook{})
+	keep, gone := &modelBook{Title: "kept"}, &modelBook{Title: "gone"}
+	d.Create(keep)
+	d.Create(gone)
+	if err := d.Delete(gone).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var books []modelBook
+	if err := d.Find(&books).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(books) != 1 || books[0].ID != keep.ID {
+		t.Errorf("Find() = %+v, want only the live book", books)
+	}
+
+	books = nil
+	if err := Unscoped(context.Background()).Find(&books).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(books) != 2 {
+		t.Errorf("Unscoped Find() returned %d books, want 2", len(books))
+	}
+	for _, b := range books {
+		if b.ID == gone.ID && b.DeletedAt == nil {
+			t.Error("soft-deleted book has no DeletedAt")
+		}
+	}
+}
+
+func TestNotDeletedJoin(t *testing.T) {
+	d := connectTest(t, &modelAuthor{}, &modelBook{})
+	a := &modelAuthor{Name: "Herbert"}
+	d.Create(a)
+	d.Create(&modelBook{Title: "Dune", AuthorID: a.ID})
+	gone := &modelBook{Title: "gone", AuthorID: a.ID}
+	d.Create(gone)
+	d.Delete(gone)
+
+	tests := []struct {
+		name  string
+		table string
+	}{
+		{"table", "model_books"},
+		{"alias", "model_books AS b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Both tables have deleted_at, so an unqualified column would
+			// be ambiguous.
+			var n int
+			err := d.Table(tt.table).
+				Joins("JOIN model_authors ON model_authors.id = author_id").
+				Scopes(NotDeleted).
+				Count(&n).Error
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != 1 {
+				t.Errorf("count = %d, want only the live book", n)
+			}
+		})
+	}
+}
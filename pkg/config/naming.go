@@ -0,0 +1,72 @@
+package config
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	tablePrefix    atomic.Value // string
+	prefixHandler  sync.Once
+	singularTables *bool
+)
+
+// SetTablePrefix prepends prefix to every table name GORM derives from a
+// model. Models with a TableName method keep the name it returns, whether
+// they are queried one at a time or into a slice. The table-name handler is
+// global, so this applies to every connection, including ones opened
+// outside this package. An empty prefix restores the default names.
+func SetTablePrefix(prefix string) {
+	tablePrefix.Store(prefix)
+	prefixHandler.Do(func() {
+		next := gorm.DefaultTableNameHandler
+		gorm.DefaultTableNameHandler = func(d *gorm.DB, name string) string {
+			name = next(d, name)
+			if name == "" || isTableNameOf(d, name) {
+				// No model to name a table after, or the model names its
+				// own table.
+				return name
+			}
+			p, _ := tablePrefix.Load().(string)
+			return p + name
+		}
+	})
+}
+
+// isTableNameOf reports whether name is what the TableName method of the
+// model behind d returns. GORM only skips the table-name handler for such
+// models when the session's value is the model itself; for a slice
+// destination such as Find(&[]Model{}) it passes TableName's result through
+// the handler like a derived name.
+func isTableNameOf(d *gorm.DB, name string) bool {
+	if d == nil || d.Value == nil {
+		return false
+	}
+	t := reflect.TypeOf(d.Value)
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	tabler, ok := reflect.New(t).Interface().(interface{ TableName() string })
+	return ok && tabler.TableName() == name
+}
+
+// SetSingularTable turns off GORM's pluralization of table names. It may be
+// called before Connect, in which case it is applied once the connection
+// opens. GORM caches a model's table name on first use, so it must be
+// called before models are used.
+func SetSingularTable(singular bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	singularTables = &singular
+	forEachConn(applyNaming)
+}
+
+// applyNaming must be called with mu held.
+func applyNaming(d *gorm.DB) {
+	if singularTables != nil {
+		d.SingularTable(*singularTables)
+	}
+}
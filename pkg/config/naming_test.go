@@ -0,0 +1,122 @@
+//go:build sqlite
+
+package config
+
+import (
+	"context"
+	"testing"
+)
+
+type namingItem struct {
+	ID uint
+}
+
+type namingLegacy struct {
+	ID uint
+}
+
+func (namingLegacy) TableName() string { return "legacy_things" }
+
+type namingSingular struct {
+	ID uint
+}
+
+type namingSingularEarly struct {
+	ID uint
+}
+
+func useTablePrefix(t *testing.T, prefix string) {
+	t.Helper()
+	SetTablePrefix(prefix)
+	t.Cleanup(func() { SetTablePrefix("") })
+}
+
+func useSingularTable(t *testing.T, singular bool) {
+	t.Helper()
+	SetSingularTable(singular)
+	t.Cleanup(func() {
+		mu.Lock()
+		singularTables = nil
+		mu.Unlock()
+	})
+}
+
+func TestSetTablePrefix(t *testing.T) {
+	useTablePrefix(t, "app_")
+	d := connectTest(t, &namingItem{}, &namingLegacy{})
+
+	tests := []struct {
+		name  string
+		model interface{}
+		want  string
+	}{
+		{"derived name", &namingItem{}, "app_naming_items"},
+		{"TableName method", &namingLegacy{}, "legacy_things"},
+		{"no model", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.NewScope(tt.model).TableName(); got != tt.want {
+				t.Errorf("TableName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	for _, table := range []string{"app_naming_items", "legacy_things"} {
+		if !d.HasTable(table) {
+			t.Errorf("AutoMigrate did not create %s", table)
+		}
+	}
+
+	SetTablePrefix("")
+	if got := d.NewScope(&namingItem{}).TableName(); got != "naming_items" {
+		t.Errorf("TableName() = %q after clearing the prefix, want naming_items", got)
+	}
+}
+
+// TestSetTablePrefixSlices loads into slices, where GORM hands even a
+// TableName method's result to the table-name handler.
+func TestSetTablePrefixSlices(t *testing.T) {
+	useTablePrefix(t, "app_")
+	d := connectTest(t, &namingItem{}, &namingLegacy{})
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		d.Create(&namingItem{})
+		d.Create(&namingLegacy{})
+	}
+
+	var items []namingItem
+	if err := d.Find(&items).Error; err != nil || len(items) != 3 {
+		t.Errorf("Find(&[]namingItem{}) = %d rows, %v; want 3 from app_naming_items", len(items), err)
+	}
+	var legacy []namingLegacy
+	if err := d.Find(&legacy).Error; err != nil || len(legacy) != 3 {
+		t.Errorf("Find(&[]namingLegacy{}) = %d rows, %v; want 3 from legacy_things", len(legacy), err)
+	}
+	if got, err := (Repository[namingLegacy]{}).List(ctx); err != nil || len(got) != 3 {
+		t.Errorf("Repository.List() = %d rows, %v; want 3", len(got), err)
+	}
+	n := 0
+	err := FindInBatches(ctx, 2, func(batch []namingLegacy) error {
+		n += len(batch)
+		return nil
+	})
+	if err != nil || n != 3 {
+		t.Errorf("FindInBatches() visited %d rows, %v; want 3", n, err)
+	}
+}
+
+func TestSetSingularTable(t *testing.T) {
+	d := connectTest(t)
+	useSingularTable(t, true)
+	if got := d.NewScope(&namingSingular{}).TableName(); got != "naming_singular" {
+		t.Errorf("TableName() = %q, want naming_singular", got)
+	}
+}
+
+func TestSetSingularTableBeforeConnect(t *testing.T) {
+	useSingularTable(t, true)
+	d := connectTest(t, &namingSingularEarly{})
+	if !d.HasTable("naming_singular_early") {
+		t.Error("AutoMigrate did not create naming_singular_early")
+	}
+}
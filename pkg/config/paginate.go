@@ -0,0 +1,110 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	defaultPageSize  = 20
+	maxPageSize      = 100
+	defaultBatchSize = 1000
+)
+
+// PageResult is one page of T together with the totals needed to render
+// pagination controls.
+type PageResult[T any] struct {
+	Items      []T   `json:"items"`
+	Total      int64 `json:"total"`
+	Page       int   `json:"page"`
+	PageSize   int   `json:"pageSize"`
+	TotalPages int   `json:"totalPages"`
+}
+
+func normalizePage(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	switch {
+	case pageSize <= 0:
+		pageSize = defaultPageSize
+	case pageSize > maxPageSize:
+		pageSize = maxPageSize
+	}
+	return page, pageSize
+}
+
+// Paginate is a scope applying the offset and limit for a 1-based page.
+// Pages below 1 are treated as page 1; pageSize defaults to 20 and is
+// capped at 100.
+func Paginate(page, pageSize int) func(*gorm.DB) *gorm.DB {
+	page, pageSize = normalizePage(page, pageSize)
+	return func(d *gorm.DB) *gorm.DB {
+		return d.Offset((page - 1) * pageSize).Limit(pageSize)
+	}
+}
+
+// FindPage counts the rows of T matching scopes and loads the requested
+// page of them.
+func FindPage[T any](ctx context.Context, page, pageSize int, scopes ...func(*gorm.DB) *gorm.DB) (PageResult[T], error) {
+	page, pageSize = normalizePage(page, pageSize)
+	res := PageResult[T]{Page: page, PageSize: pageSize}
+
+	d := GetDBContext(ctx)
+	if d == nil {
+		return res, ErrNotConnected
+	}
+	q := d.Model(new(T)).Scopes(scopes...)
+	if err := q.Count(&res.Total).Error; err != nil {
+		return res, err
+	}
+	if err := q.Scopes(Paginate(page, pageSize)).Find(&res.Items).Error; err != nil {
+		return res, err
+	}
+	res.TotalPages = int((res.Total + int64(pageSize) - 1) / int64(pageSize))
+	return res, nil
+}
+
+// FindInBatches loads every row of T in primary-key order, batchSize rows
+// at a time (1000 when batchSize <= 0), and calls fn with each batch. Each
+// batch starts after the last key of the previous one rather than at an
+// offset, so deep batches stay cheap. It stops at the first error from fn
+// and returns it. Soft-deleted rows are skipped.
+func FindInBatches[T any](ctx context.Context, batchSize int, fn func(batch []T) error) error {
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	d := GetDBContext(ctx)
+	if d == nil {
+		return ErrNotConnected
+	}
+	scope := d.NewScope(new(T))
+	if len(scope.PrimaryFields()) != 1 {
+		return fmt.Errorf("config: FindInBatches: %s needs a single-column primary key", scope.TableName())
+	}
+	pk := scope.Quote(scope.PrimaryKey())
+
+	var last interface{}
+	for {
+		q := d.Model(new(T)).Order(pk).Limit(batchSize)
+		if last != nil {
+			q = q.Where(pk+" > ?", last)
+		}
+		var batch []T
+		if err := q.Find(&batch).Error; err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+		last = d.NewScope(&batch[len(batch)-1]).PrimaryKeyValue()
+	}
+}
@@ -0,0 +1,116 @@
+//go:build sqlite
+
+package config
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// insertPageItems inserts n pageItems named after their position.
+func insertPageItems(t *testing.T, n int) {
+	t.Helper()
+	items := make([]pageItem, n)
+	for i := range items {
+		items[i].Name = string(rune('a' + i%26))
+	}
+	if err := BatchCreate(context.Background(), items, 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindPage(t *testing.T) {
+	connectTest(t, &pageItem{})
+	insertPageItems(t, 45)
+
+	res, err := FindPage[pageItem](context.Background(), 3, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 45 || res.TotalPages != 3 || res.Page != 3 || res.PageSize != 20 {
+		t.Errorf("FindPage() = total %d, %d pages, page %d of size %d; want 45, 3, 3, 20",
+			res.Total, res.TotalPages, res.Page, res.PageSize)
+	}
+	if len(res.Items) != 5 || res.Items[0].ID != 41 {
+		t.Errorf("FindPage() returned %d items starting at %+v, want the last 5 from ID 41", len(res.Items), res.Items)
+	}
+}
+
+type pageCompound struct {
+	Shelf uint `gorm:"primary_key;auto_increment:false"`
+	Slot  uint `gorm:"primary_key;auto_increment:false"`
+}
+
+func TestFindInBatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		rows      int
+		batchSize int
+		wantSizes []int
+	}{
+		{"partial last batch", 2500, 1000, []int{1000, 1000, 500}},
+		{"exact multiple", 600, 200, []int{200, 200, 200}},
+		{"default batch size", 1500, 0, []int{1000, 500}},
+		{"empty table", 0, 100, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connectTest(t, &pageItem{})
+			insertPageItems(t, tt.rows)
+
+			seen := make(map[uint]bool, tt.rows)
+			var sizes []int
+			err := FindInBatches(context.Background(), tt.batchSize, func(batch []pageItem) error {
+				sizes = append(sizes, len(batch))
+				for _, it := range batch {
+					if seen[it.ID] {
+						t.Errorf("row %d visited twice", it.ID)
+					}
+					seen[it.ID] = true
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(seen) != tt.rows {
+				t.Errorf("visited %d rows, want %d", len(seen), tt.rows)
+			}
+			if !reflect.DeepEqual(sizes, tt.wantSizes) {
+				t.Errorf("batch sizes = %v, want %v", sizes, tt.wantSizes)
+			}
+		})
+	}
+}
+
+func TestFindInBatchesStopsOnError(t *testing.T) {
+	connectTest(t, &pageItem{})
+	insertPageItems(t, 50)
+
+	stop := errors.New("stop")
+	calls := 0
+	err := FindInBatches(context.Background(), 10, func([]pageItem) error {
+		calls++
+		if calls == 2 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("FindInBatches() = %v, want fn's error", err)
+	}
+	if calls != 2 {
+		t.Errorf("fn called %d times, want the loop to stop after 2", calls)
+	}
+}
+
+func TestFindInBatchesCompositeKey(t *testing.T) {
+	connectTest(t, &pageCompound{})
+	err := FindInBatches(context.Background(), 10, func([]pageCompound) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "single-column primary key") {
+		t.Errorf("FindInBatches() = %v, want a primary key error", err)
+	}
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+)
+
+type pageItem struct {
+	ID   uint
+	Name string
+}
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		name           string
+		page, pageSize int
+		want           string
+	}{
+		{"first page", 1, 10, "LIMIT 10 OFFSET 0"},
+		{"third page", 3, 10, "LIMIT 10 OFFSET 20"},
+		{"page zero is page one", 0, 10, "LIMIT 10 OFFSET 0"},
+		{"negative page is page one", -4, 10, "LIMIT 10 OFFSET 0"},
+		{"default page size", 2, 0, "LIMIT 20 OFFSET 20"},
+		{"page size capped", 2, 500, "LIMIT 100 OFFSET 100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, err := DryRun(func(tx *gorm.DB) {
+				tx.Scopes(Paginate(tt.page, tt.pageSize)).Find(&[]pageItem{})
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasSuffix(sql, tt.want) {
+				t.Errorf("Paginate(%d, %d) ran %q, want it to end in %q", tt.page, tt.pageSize, sql, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	defaultMaxOpenConns    = 25
+	defaultMaxIdleConns    = 5
+	defaultConnMaxLifetime = 5 * time.Minute
+)
+
+// PoolConfig tunes the sql.DB pool behind the gorm connection. Zero fields
+// fall back to the package defaults, except ConnMaxLifetimeJitter which
+// defaults to none.
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+
+	// ConnMaxLifetimeJitter spreads connection expiry over
+	// [ConnMaxLifetime, ConnMaxLifetime+ConnMaxLifetimeJitter]. database/sql
+	// has a single lifetime for the whole pool, so a background goroutine
+	// redraws it from that window every ConnMaxLifetimeJitter (at least
+	// once a second) until the pool is closed. This spreads out connections
+	// opened at different times; ones opened at the same moment still
+	// expire together.
+	ConnMaxLifetimeJitter time.Duration
+}
+
+func (c PoolConfig) withDefaults() PoolConfig {
+	if c.MaxOpenConns == 0 {
+		c.MaxOpenConns = defaultMaxOpenConns
+	}
+	if c.MaxIdleConns == 0 {
+		c.MaxIdleConns = defaultMaxIdleConns
+	}
+	if c.ConnMaxLifetime == 0 {
+		c.ConnMaxLifetime = defaultConnMaxLifetime
+	}
+	return c
+}
+
+func (c PoolConfig) apply(d *gorm.DB) {
+	c = c.withDefaults()
+	d.DB().SetMaxOpenConns(c.MaxOpenConns)
+	d.DB().SetMaxIdleConns(c.MaxIdleConns)
+	d.DB().SetConnMaxLifetime(c.ConnMaxLifetime)
+	if c.ConnMaxLifetimeJitter > 0 {
+		go jitterLifetime(d.DB(), c.ConnMaxLifetime, c.ConnMaxLifetimeJitter)
+	}
+}
+
+// jitterLifetime sets a random lifetime in [lifetime, lifetime+jitter] on
+// sqlDB at every tick until sqlDB is closed.
+func jitterLifetime(sqlDB *sql.DB, lifetime, jitter time.Duration) {
+	t := time.NewTicker(max(jitter, time.Second))
+	defer t.Stop()
+	for range t.C {
+		if poolClosed(sqlDB) {
+			return
+		}
+		sqlDB.SetConnMaxLifetime(lifetime + rand.N(jitter+1))
+	}
+}
+
+// poolClosed reports whether sqlDB has been closed. database/sql checks for
+// a closed pool before a cancelled context, so pinging with one never
+// touches a connection.
+func poolClosed(sqlDB *sql.DB) bool {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return !errors.Is(sqlDB.PingContext(ctx), context.Canceled)
+}
+
+// Stats returns the statistics of the connection pool.
+func Stats() (sql.DBStats, error) {
+	sqlDB, err := SQLDB()
+	if err != nil {
+		return sql.DBStats{}, err
+	}
+	return sqlDB.Stats(), nil
+}
+
+// WarmUp opens up to n pool connections up front by pinging on n
+// connections held at the same time, so the first requests after startup do
+// not pay for dialing. n is capped at the pool's MaxOpenConns; connections
+// beyond MaxIdleConns are closed again once released. Ping failures are
+// returned together.
+func WarmUp(n int) error {
+	sqlDB, err := SQLDB()
+	if err != nil {
+		return err
+	}
+	if max := sqlDB.Stats().MaxOpenConnections; max > 0 && n > max {
+		n = max
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	var (
+		acquired sync.WaitGroup
+		done     sync.WaitGroup
+		release  = make(chan struct{})
+		errs     = make([]error, n)
+	)
+	acquired.Add(n)
+	done.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer done.Done()
+			ctx := context.Background()
+			conn, err := sqlDB.Conn(ctx)
+			if err == nil {
+				err = conn.PingContext(ctx)
+			}
+			errs[i] = err
+			acquired.Done()
+			if conn != nil {
+				// Hold the connection until every goroutine has one, so
+				// the pool cannot hand the same connection out twice.
+				<-release
+				conn.Close()
+			}
+		}(i)
+	}
+	acquired.Wait()
+	close(release)
+	done.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("config: warming up connection pool: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+//go:build sqlite
+
+package config
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPoolConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         PoolConfig
+		wantMaxOpen int
+	}{
+		{"defaults", PoolConfig{}, defaultMaxOpenConns},
+		{"configured", PoolConfig{MaxOpenConns: 7, MaxIdleConns: 2, ConnMaxLifetime: time.Minute}, 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := open("sqlite3", ":memory:", tt.cfg); err != nil {
+				t.Fatal(err)
+			}
+			defer Close()
+			if got := GetBD().DB().Stats().MaxOpenConnections; got != tt.wantMaxOpen {
+				t.Errorf("MaxOpenConnections = %d, want %d", got, tt.wantMaxOpen)
+			}
+		})
+	}
+}
+
+func TestStats(t *testing.T) {
+	if _, err := Stats(); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Stats() before connecting = %v, want ErrNotConnected", err)
+	}
+
+	d := connectTest(t)
+	if err := d.Exec("SELECT 1").Error; err != nil {
+		t.Fatal(err)
+	}
+	stats, err := Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.OpenConnections < 1 {
+		t.Errorf("OpenConnections = %d after a query, want at least 1", stats.OpenConnections)
+	}
+}
+
+func TestWarmUp(t *testing.T) {
+	tests := []struct {
+		name     string
+		n        int
+		wantOpen int
+	}{
+		{"below the limit", 3, 3},
+		{"capped at MaxOpenConns", 10, 4},
+		// gorm.Open's ping leaves one connection behind.
+		{"zero", 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := open("sqlite3", ":memory:", PoolConfig{MaxOpenConns: 4, MaxIdleConns: 10}); err != nil {
+				t.Fatal(err)
+			}
+			defer Close()
+			if err := WarmUp(tt.n); err != nil {
+				t.Fatal(err)
+			}
+			stats := GetBD().DB().Stats()
+			if stats.OpenConnections != tt.wantOpen || stats.InUse != 0 {
+				t.Errorf("WarmUp(%d) left %d open, %d in use; want %d open and none in use",
+					tt.n, stats.OpenConnections, stats.InUse, tt.wantOpen)
+			}
+		})
+	}
+}
+
+func TestWarmUpNotConnected(t *testing.T) {
+	if err := WarmUp(3); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("WarmUp() before connecting = %v, want ErrNotConnected", err)
+	}
+}
+
+// jitterGoroutines returns the number of running jitterLifetime goroutines.
+func jitterGoroutines() int {
+	buf := make([]byte, 1<<20)
+	buf = buf[:runtime.Stack(buf, true)]
+	return strings.Count(string(buf), "config.jitterLifetime(")
+}
+
+// waitJitterGoroutines waits for the number of jitterLifetime goroutines to
+// reach n. A goroutine that has not been scheduled yet may not show up in
+// the stack dump, and one exits only on its next tick.
+func waitJitterGoroutines(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for jitterGoroutines() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d jitter goroutines still running, want %d", jitterGoroutines(), n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestConnMaxLifetimeJitter(t *testing.T) {
+	t.Cleanup(func() { Close() })
+	base := jitterGoroutines()
+
+	if err := open("sqlite3", ":memory:", PoolConfig{ConnMaxLifetime: time.Minute}); err != nil {
+		t.Fatal(err)
+	}
+	if got := dialed.pool.ConnMaxLifetimeJitter; got != 0 {
+		t.Errorf("stored jitter = %s, want 0", got)
+	}
+	time.Sleep(10 * time.Millisecond) // let a stray goroutine get scheduled
+	if n := jitterGoroutines(); n != base {
+		t.Errorf("zero jitter started %d jitter goroutines, want none", n-base)
+	}
+	Close()
+
+	cfg := PoolConfig{ConnMaxLifetime: time.Minute, ConnMaxLifetimeJitter: 10 * time.Millisecond}
+	if err := open("sqlite3", ":memory:", cfg); err != nil {
+		t.Fatal(err)
+	}
+	if got := dialed.pool.ConnMaxLifetimeJitter; got != cfg.ConnMaxLifetimeJitter {
+		t.Errorf("stored jitter = %s, want %s for reconnects", got, cfg.ConnMaxLifetimeJitter)
+	}
+	waitJitterGoroutines(t, base+1)
+
+	Close()
+	waitJitterGoroutines(t, base)
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"context"
+
+	"github.com/jinzhu/gorm"
+)
+
+// where applies conds to a query on T the way GORM's Where does: the first
+// element is the condition, the rest are its arguments.
+func where[T any](ctx context.Context, conds []interface{}) (*gorm.DB, error) {
+	d := GetDBContext(ctx)
+	if d == nil {
+		return nil, ErrNotConnected
+	}
+	q := d.Model(new(T))
+	if len(conds) > 0 {
+		q = q.Where(conds[0], conds[1:]...)
+	}
+	return q, nil
+}
+
+// Exists reports whether any row of T matches conds, using SELECT 1 ...
+// LIMIT 1 so no model is loaded. Soft-deleted rows do not count.
+func Exists[T any](ctx context.Context, conds ...interface{}) (bool, error) {
+	q, err := where[T](ctx, conds)
+	if err != nil {
+		return false, err
+	}
+	rows, err := q.Select("1").Limit(1).Rows()
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer rows.Close()
+	found := rows.Next()
+	return found, rows.Err()
+}
+
+// Count returns the number of rows of T matching conds. Soft-deleted rows
+// are not counted.
+func Count[T any](ctx context.Context, conds ...interface{}) (int64, error) {
+	q, err := where[T](ctx, conds)
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	if err := q.Count(&n).Error; err != nil {
+		return 0, err
+	}
+	return n, nil
+}
@@ -0,0 +1,112 @@
+//go:build sqlite
+
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type queryBook struct {
+	BaseModel
+	Title  string
+	Author string
+}
+
+type queryMissing struct {
+	ID uint
+}
+
+// seedQueryBooks inserts three books by two authors and soft-deletes the
+// last one.
+func seedQueryBooks(t *testing.T) {
+	t.Helper()
+	d := connectTest(t, &queryBook{})
+	books := []queryBook{
+		{Title: "Dune", Author: "Herbert"},
+		{Title: "Emma", Author: "Austen"},
+		{Title: "Persuasion", Author: "Austen"},
+	}
+	for i := range books {
+		if err := d.Create(&books[i]).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := d.Delete(&books[2]).Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExists(t *testing.T) {
+	seedQueryBooks(t)
+	ctx := context.Background()
+	tests := []struct {
+		name  string
+		conds []interface{}
+		want  bool
+	}{
+		{"no conditions", nil, true},
+		{"match", []interface{}{"title = ?", "Dune"}, true},
+		{"struct condition", []interface{}{&queryBook{Author: "Austen"}}, true},
+		{"no match", []interface{}{"title = ?", "Ulysses"}, false},
+		{"soft-deleted", []interface{}{"title = ?", "Persuasion"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Exists[queryBook](ctx, tt.conds...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Exists(%v) = %v, want %v", tt.conds, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExistsErrors(t *testing.T) {
+	if _, err := Exists[queryBook](context.Background()); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Exists() before Connect = %v, want ErrNotConnected", err)
+	}
+	connectTest(t)
+	if _, err := Exists[queryMissing](context.Background()); err == nil {
+		t.Error("Exists() on a missing table = nil error")
+	}
+}
+
+func TestCount(t *testing.T) {
+	seedQueryBooks(t)
+	ctx := context.Background()
+	tests := []struct {
+		name  string
+		conds []interface{}
+		want  int64
+	}{
+		{"all live rows", nil, 2},
+		{"filtered", []interface{}{"author = ?", "Herbert"}, 1},
+		{"soft-deleted excluded", []interface{}{"author = ?", "Austen"}, 1},
+		{"no match", []interface{}{"author = ?", "Joyce"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Count[queryBook](ctx, tt.conds...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Count(%v) = %d, want %d", tt.conds, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountErrors(t *testing.T) {
+	if _, err := Count[queryBook](context.Background()); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Count() before Connect = %v, want ErrNotConnected", err)
+	}
+	connectTest(t)
+	if _, err := Count[queryMissing](context.Background()); err == nil {
+		t.Error("Count() on a missing table = nil error")
+	}
+}
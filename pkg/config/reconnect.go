@@ -0,0 +1,117 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	defaultReconnectInterval = 30 * time.Second
+	maxReconnectBackoff      = time.Minute
+)
+
+// reconnectDrainTimeout bounds how long a replaced pool is kept open for
+// the sessions still using it. It is a variable so tests can shorten it.
+var reconnectDrainTimeout = 30 * time.Second
+
+// StartAutoReconnect pings the package connection every interval in a
+// background goroutine and re-opens it with the settings of the last
+// successful Connect when the ping fails. Failed attempts back off
+// exponentially up to one minute. The loop stops when ctx is cancelled.
+// Connections closed with Close are left closed. The replaced pool is closed
+// once the connections in use on it are released, or after 30 seconds.
+// Since a reconnect swaps the handle returned by GetBD, callers should fetch
+// it per use, not cache it.
+func StartAutoReconnect(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultReconnectInterval
+	}
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+
+		backoff := interval
+		var next time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-t.C:
+				if now.Before(next) {
+					continue
+				}
+				pingCtx, cancel := context.WithTimeout(ctx, interval)
+				err := Ping(pingCtx)
+				cancel()
+				if err == nil || errors.Is(err, ErrNotConnected) || ctx.Err() != nil {
+					backoff = interval
+					next = time.Time{}
+					continue
+				}
+
+				logf("config: database unreachable, reconnecting: %v", err)
+				if err := reconnect(); err != nil {
+					logf("config: reconnect failed, retrying in %s: %v", backoff, err)
+					next = now.Add(backoff)
+					backoff = min(backoff*2, maxReconnectBackoff)
+					continue
+				}
+				logf("config: reconnected to database")
+				backoff = interval
+				next = time.Time{}
+			}
+		}
+	}()
+}
+
+// reconnect replaces the package connection with a freshly dialed one.
+func reconnect() error {
+	mu.RLock()
+	args, old := dialed, db
+	mu.RUnlock()
+	if args == nil {
+		return errors.New("config: no connection settings to reconnect with")
+	}
+	if old == nil {
+		// Closed; there is nothing to replace.
+		return nil
+	}
+
+	d, err := dial(args.dialect, args.dsn, args.pool)
+	if err != nil {
+		return err
+	}
+	if !swapConn(old, d) {
+		return d.Close()
+	}
+	go drainAndClose(old, reconnectDrainTimeout)
+	return nil
+}
+
+// drainAndClose closes a replaced pool once the sessions taken from it
+// before the swap are done with it, or after timeout.
+func drainAndClose(old *gorm.DB, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if inUse, err := waitIdle(ctx, old.DB()); err != nil {
+		logf("config: closing replaced pool with %d connections still in use", inUse)
+	}
+	old.Close()
+}
+
+// swapConn makes d the package connection if it still is old, and reports
+// whether it did. Close, or Close followed by Connect, may have run while
+// d was being dialed.
+func swapConn(old, d *gorm.DB) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	if db != old {
+		return false
+	}
+	applySettings(d)
+	db = d
+	return true
+}
@@ -0,0 +1,143 @@
+//go:build sqlite
+
+package config
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func openReconnectTest(t *testing.T) {
+	t.Helper()
+	if err := open("sqlite3", "file:reconnect?mode=memory&cache=shared", PoolConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { Close() })
+}
+
+// TestStartAutoReconnect breaks the pool under the loop and waits for it to
+// be replaced with a working one.
+func TestStartAutoReconnect(t *testing.T) {
+	l := useLogger(t)
+	openReconnectTest(t)
+	old := GetBD()
+	if err := old.DB().Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := Ping(context.Background()); err == nil {
+		t.Fatal("Ping() = nil on a closed pool")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	StartAutoReconnect(ctx, 10*time.Millisecond)
+
+	// The loop logs once the new connection is in place.
+	deadline := time.Now().Add(2 * time.Second)
+	for !l.logged("reconnected to database") {
+		if time.Now().After(deadline) {
+			t.Fatal("connection not recovered within 2s")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := Ping(context.Background()); err != nil {
+		t.Errorf("Ping() = %v after reconnecting", err)
+	}
+	if GetBD() == old {
+		t.Error("GetBD() still returns the closed connection")
+	}
+}
+
+// waitPoolClosed waits for sqlDB to be closed.
+func waitPoolClosed(t *testing.T, sqlDB *sql.DB, within time.Duration) {
+	t.Helper()
+	deadline := time.Now().Add(within)
+	for !poolClosed(sqlDB) {
+		if time.Now().After(deadline) {
+			t.Fatalf("replaced pool still open after %s", within)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReconnectDrainsOldPool(t *testing.T) {
+	openReconnectTest(t)
+	old := GetBD().DB()
+	conn, err := old.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := reconnect(); err != nil {
+		t.Fatalf("reconnect() = %v", err)
+	}
+	if GetBD().DB() == old {
+		t.Fatal("reconnect() did not swap the connection")
+	}
+	// A session still using the old pool keeps working until it is done.
+	time.Sleep(3 * shutdownPollInterval)
+	if err := conn.PingContext(context.Background()); err != nil {
+		t.Errorf("held connection Ping() = %v, want the old pool kept open", err)
+	}
+	conn.Close()
+	waitPoolClosed(t, old, time.Second)
+}
+
+func TestReconnectDrainTimeout(t *testing.T) {
+	l := useLogger(t)
+	openReconnectTest(t)
+	saved := reconnectDrainTimeout
+	reconnectDrainTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { reconnectDrainTimeout = saved })
+
+	old := GetBD().DB()
+	conn, err := old.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if err := reconnect(); err != nil {
+		t.Fatalf("reconnect() = %v", err)
+	}
+	waitPoolClosed(t, old, time.Second)
+	if !l.logged("closing replaced pool with 1 connections still in use") {
+		t.Error("closing a busy replaced pool was not logged")
+	}
+}
+
+func TestReconnectAfterClose(t *testing.T) {
+	openReconnectTest(t)
+	if err := Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := reconnect(); err != nil {
+		t.Fatalf("reconnect() = %v", err)
+	}
+	if d := GetBD(); d != nil {
+		t.Errorf("GetBD() = %v after reconnecting a closed connection, want nil", d)
+	}
+}
+
+// TestReconnectAfterReplace covers Close and Connect running while a
+// reconnect is dialing: the connection opened meanwhile must survive.
+func TestReconnectAfterReplace(t *testing.T) {
+	openReconnectTest(t)
+	old := GetBD()
+	Close()
+	openReconnectTest(t)
+	current := GetBD()
+
+	d, err := dial(dialed.dialect, dialed.dsn, dialed.pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if swapConn(old, d) {
+		t.Fatal("swapConn() replaced a connection opened after the reconnect started")
+	}
+	d.Close()
+	if GetBD() != current {
+		t.Error("GetBD() changed, want the connection opened by the later Connect")
+	}
+}
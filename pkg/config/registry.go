@@ -0,0 +1,100 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jinzhu/gorm"
+)
+
+// DefaultName is the registry name of the package connection managed by
+// Connect and GetBD.
+const DefaultName = "default"
+
+var named = map[string]*gorm.DB{}
+
+// Register opens the connection described by cfg and stores it under name.
+// It fails if name is already registered; use Replace to swap it.
+func Register(name string, cfg Config) error {
+	return register(name, cfg, false)
+}
+
+// Replace is like Register but closes and replaces an existing connection
+// registered under name.
+func Replace(name string, cfg Config) error {
+	return register(name, cfg, true)
+}
+
+func register(name string, cfg Config, overwrite bool) error {
+	if name == DefaultName {
+		return errors.New("config: the default connection is opened with Connect, not Register")
+	}
+	if !overwrite && isRegistered(name) {
+		return fmt.Errorf("config: connection %q is already registered", name)
+	}
+	if err := cfg.prepare(); err != nil {
+		return err
+	}
+	d, err := dial(cfg.dialect(), cfg.DSN(), PoolConfig{})
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	old, exists := named[name]
+	if exists && !overwrite {
+		d.Close()
+		return fmt.Errorf("config: connection %q is already registered", name)
+	}
+	applySettings(d)
+	named[name] = d
+	if exists {
+		old.Close()
+	}
+	return nil
+}
+
+func isRegistered(name string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	_, ok := named[name]
+	return ok
+}
+
+// Get returns the connection registered under name. DefaultName returns the
+// package connection from GetBD.
+func Get(name string) (*gorm.DB, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if name == DefaultName {
+		if db == nil {
+			return nil, ErrNotConnected
+		}
+		return db, nil
+	}
+	d, ok := named[name]
+	if !ok {
+		return nil, fmt.Errorf("config: no connection registered as %q", name)
+	}
+	return d, nil
+}
+
+// CloseAll closes every registered connection as well as the package
+// connection and its replicas. It returns the last error encountered.
+func CloseAll() error {
+	mu.Lock()
+	var err error
+	for name, d := range named {
+		if cerr := d.Close(); cerr != nil {
+			err = fmt.Errorf("config: closing %q: %w", name, cerr)
+		}
+		delete(named, name)
+	}
+	mu.Unlock()
+
+	if cerr := Close(); cerr != nil {
+		err = cerr
+	}
+	return err
+}
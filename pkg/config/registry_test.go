@@ -0,0 +1,86 @@
+//go:build sqlite
+
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+)
+
+// registerTest stores an in-memory SQLite database under name, standing in
+// for a connection opened by Register.
+func registerTest(t *testing.T, name string) *gorm.DB {
+	t.Helper()
+	d, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	named[name] = d
+	mu.Unlock()
+	t.Cleanup(func() { CloseAll() })
+	return d
+}
+
+// unreachable is a valid Config for a server that refuses connections.
+var unreachable = Config{User: "u", Host: "127.0.0.1", Port: 1, Database: "shop"}
+
+func TestRegistryGet(t *testing.T) {
+	primary := connectTest(t)
+	reports := registerTest(t, "reports")
+	billing := registerTest(t, "billing")
+
+	for name, want := range map[string]*gorm.DB{"reports": reports, "billing": billing, DefaultName: primary} {
+		got, err := Get(name)
+		if err != nil {
+			t.Errorf("Get(%q) = %v", name, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("Get(%q) = %p, want %p", name, got, want)
+		}
+	}
+	if _, err := Get("missing"); err == nil || !strings.Contains(err.Error(), `no connection registered as "missing"`) {
+		t.Errorf("Get(missing) = %v, want a not-registered error", err)
+	}
+}
+
+func TestRegistryRegister(t *testing.T) {
+	reports := registerTest(t, "reports")
+
+	if err := Register("reports", unreachable); err == nil || !strings.Contains(err.Error(), "already registered") {
+		t.Errorf("Register(reports) = %v, want an already-registered error", err)
+	}
+	if err := Register(DefaultName, unreachable); err == nil {
+		t.Error("Register(DefaultName) succeeded, want an error")
+	}
+	if err := Replace("reports", unreachable); err == nil {
+		t.Error("Replace with an unreachable server succeeded")
+	}
+	if got, _ := Get("reports"); got != reports {
+		t.Errorf("Get(reports) = %p after a failed Replace, want the old connection", got)
+	}
+	if err := Register("billing", Config{}); err == nil {
+		t.Error("Register with an invalid Config succeeded")
+	}
+	if _, err := Get("billing"); err == nil {
+		t.Error("a failed Register left a connection behind")
+	}
+}
+
+func TestRegistryCloseAll(t *testing.T) {
+	connectTest(t)
+	registerTest(t, "reports")
+	if err := CloseAll(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get("reports"); err == nil {
+		t.Error("Get(reports) succeeded after CloseAll")
+	}
+	if _, err := Get(DefaultName); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Get(DefaultName) = %v after CloseAll, want ErrNotConnected", err)
+	}
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"sync/atomic"
+
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	replicas    []*gorm.DB
+	replicaNext atomic.Uint64
+)
+
+// ConnectReplicas opens a mysql connection to each DSN in dsns for use by
+// GetReadDB, replacing any replicas opened before. If one of them fails,
+// the ones already opened are closed and the previous replicas are kept.
+func ConnectReplicas(dsns []string) error {
+	opened := make([]*gorm.DB, 0, len(dsns))
+	for _, dsn := range dsns {
+		d, err := dial(DialectMySQL, dsn, PoolConfig{})
+		if err != nil {
+			for _, o := range opened {
+				o.Close()
+			}
+			return err
+		}
+		opened = append(opened, d)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, d := range opened {
+		applySettings(d)
+	}
+	closeReplicas()
+	replicas = opened
+	return nil
+}
+
+// GetReadDB returns the next read replica in round-robin order, or the
+// primary connection from GetBD when no replicas are configured.
+func GetReadDB() *gorm.DB {
+	mu.RLock()
+	defer mu.RUnlock()
+	if len(replicas) == 0 {
+		return db
+	}
+	n := replicaNext.Add(1) - 1
+	return replicas[n%uint64(len(replicas))]
+}
+
+// closeReplicas must be called with mu held.
+func closeReplicas() error {
+	var err error
+	for _, r := range replicas {
+		if cerr := r.Close(); cerr != nil {
+			err = cerr
+		}
+	}
+	replicas = nil
+	return err
+}
@@ -0,0 +1,66 @@
+//go:build sqlite
+
+package config
+
+import (
+	"testing"
+
+	"github.com/jinzhu/gorm"
+)
+
+// useReplicas installs n in-memory SQLite databases as read replicas.
+func useReplicas(t *testing.T, n int) []*gorm.DB {
+	t.Helper()
+	var rs []*gorm.DB
+	for i := 0; i < n; i++ {
+		r, err := gorm.Open("sqlite3", ":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		rs = append(rs, r)
+	}
+	mu.Lock()
+	replicas = rs
+	mu.Unlock()
+	t.Cleanup(func() {
+		mu.Lock()
+		closeReplicas()
+		mu.Unlock()
+	})
+	return rs
+}
+
+func TestGetReadDBRoundRobin(t *testing.T) {
+	connectTest(t)
+	rs := useReplicas(t, 2)
+	first := GetReadDB()
+	if first != rs[0] && first != rs[1] {
+		t.Fatalf("GetReadDB() = %p, want one of the replicas", first)
+	}
+	prev := first
+	for i := 0; i < 4; i++ {
+		next := GetReadDB()
+		if next == prev || (next != rs[0] && next != rs[1]) {
+			t.Fatalf("call %d returned %p after %p, want the other replica", i+2, next, prev)
+		}
+		prev = next
+	}
+}
+
+func TestGetReadDBFallsBackToPrimary(t *testing.T) {
+	d := connectTest(t)
+	if got := GetReadDB(); got != d {
+		t.Errorf("GetReadDB() = %p without replicas, want the primary %p", got, d)
+	}
+}
+
+func TestConnectReplicasKeepsPreviousOnError(t *testing.T) {
+	connectTest(t)
+	rs := useReplicas(t, 1)
+	if err := ConnectReplicas([]string{"u:p@tcp(127.0.0.1:1)/shop"}); err == nil {
+		t.Fatal("ConnectReplicas succeeded against an unreachable server")
+	}
+	if got := GetReadDB(); got != rs[0] {
+		t.Errorf("GetReadDB() = %p after a failed ConnectReplicas, want the previous replica", got)
+	}
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jinzhu/gorm"
+)
+
+// ErrNotFound is returned when a lookup by primary key matches no row.
+var ErrNotFound = errors.New("config: record not found")
+
+// Repository provides typed CRUD helpers for model T on the package
+// connection. The zero value is ready to use.
+type Repository[T any] struct{}
+
+func (Repository[T]) conn(ctx context.Context) (*gorm.DB, error) {
+	d := GetDBContext(ctx)
+	if d == nil {
+		return nil, ErrNotConnected
+	}
+	return d, nil
+}
+
+// wherePK filters d by T's primary key. Passing the id to First or Delete
+// directly would treat string ids as raw SQL conditions.
+func (r Repository[T]) wherePK(d *gorm.DB, id interface{}) *gorm.DB {
+	scope := d.NewScope(new(T))
+	return d.Where(scope.Quote(scope.PrimaryKey())+" = ?", id)
+}
+
+// Create inserts v.
+func (r Repository[T]) Create(ctx context.Context, v *T) error {
+	d, err := r.conn(ctx)
+	if err != nil {
+		return err
+	}
+	return d.Create(v).Error
+}
+
+// FindByID returns the row with primary key id, or ErrNotFound.
+func (r Repository[T]) FindByID(ctx context.Context, id interface{}) (*T, error) {
+	d, err := r.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v := new(T)
+	if err := r.wherePK(d, id).First(v).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return v, nil
+}
+
+// Update saves all fields of v.
+func (r Repository[T]) Update(ctx context.Context, v *T) error {
+	d, err := r.conn(ctx)
+	if err != nil {
+		return err
+	}
+	return d.Save(v).Error
+}
+
+// Delete removes the row with primary key id. Models with a DeletedAt field
+// are soft-deleted.
+func (r Repository[T]) Delete(ctx context.Context, id interface{}) error {
+	d, err := r.conn(ctx)
+	if err != nil {
+		return err
+	}
+	return r.wherePK(d, id).Delete(new(T)).Error
+}
+
+// List returns every row of T.
+func (r Repository[T]) List(ctx context.Context) ([]T, error) {
+	d, err := r.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var items []T
+	if err := d.Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
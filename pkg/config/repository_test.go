@@ -0,0 +1,95 @@
+//go:build sqlite
+
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type repoBook struct {
+	BaseModel
+	Title string
+}
+
+type repoTag struct {
+	Code string `gorm:"primary_key"`
+	Name string
+}
+
+func TestRepositoryCRUD(t *testing.T) {
+	connectTest(t, &repoBook{})
+	ctx := context.Background()
+	var repo Repository[repoBook]
+
+	b := &repoBook{Title: "Dune"}
+	if err := repo.Create(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	if b.ID == 0 {
+		t.Fatal("Create did not set the ID")
+	}
+
+	got, err := repo.FindByID(ctx, b.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "Dune" {
+		t.Errorf("FindByID() = %+v, want the created book", got)
+	}
+
+	got.Title = "Dune Messiah"
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := repo.FindByID(ctx, b.ID); got == nil || got.Title != "Dune Messiah" {
+		t.Errorf("FindByID() after Update = %+v, want the new title", got)
+	}
+
+	if err := repo.Create(ctx, &repoBook{Title: "Emma"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Delete(ctx, b.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.FindByID(ctx, b.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("FindByID() after Delete = %v, want ErrNotFound", err)
+	}
+	list, err := repo.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Title != "Emma" {
+		t.Errorf("List() = %+v, want only Emma", list)
+	}
+}
+
+func TestRepositoryStringKey(t *testing.T) {
+	connectTest(t, &repoTag{})
+	ctx := context.Background()
+	var repo Repository[repoTag]
+	for _, tag := range []repoTag{{"go", "Go"}, {"sql", "SQL"}} {
+		if err := repo.Create(ctx, &tag); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := repo.FindByID(ctx, "sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "SQL" {
+		t.Errorf("FindByID(%q) = %+v, want the SQL tag", "sql", got)
+	}
+	// A string id must be matched as a value, never run as a condition.
+	if _, err := repo.FindByID(ctx, "1 = 1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("FindByID(%q) = %v, want ErrNotFound", "1 = 1", err)
+	}
+}
+
+func TestRepositoryNotConnected(t *testing.T) {
+	var repo Repository[repoBook]
+	if _, err := repo.List(context.Background()); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("List() = %v, want ErrNotConnected", err)
+	}
+}
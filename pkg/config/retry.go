@@ -0,0 +1,42 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const minRetryDelay = 10 * time.Millisecond
+
+// ConnectWithRetry behaves like Connect but retries failed opens with
+// exponential backoff starting at baseDelay, which is raised to 10ms if it
+// is smaller. It gives up after maxAttempts or when ctx is cancelled.
+func ConnectWithRetry(ctx context.Context, maxAttempts int, baseDelay time.Duration) error {
+	dsn, err := BuildDSN()
+	if err != nil {
+		return err
+	}
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	delay := max(baseDelay, minRetryDelay)
+	for attempt := 1; ; attempt++ {
+		err = open(DialectMySQL, dsn, PoolConfig{})
+		if err == nil {
+			return nil
+		}
+		if attempt == maxAttempts {
+			return fmt.Errorf("config: giving up after %d attempts: %w", attempt, err)
+		}
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return fmt.Errorf("config: connect cancelled after %d attempts: %w", attempt, ctx.Err())
+		case <-t.C:
+		}
+		delay *= 2
+	}
+}
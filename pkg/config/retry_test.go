@@ -0,0 +1,53 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConnectWithRetryAttempts(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxAttempts int
+		baseDelay   time.Duration
+		want        string
+		minElapsed  time.Duration
+	}{
+		{"three attempts", 3, 5 * time.Millisecond, "after 3 attempts", 3 * minRetryDelay},
+		{"at least one attempt", 0, time.Hour, "after 1 attempts", 0},
+		{"zero delay is clamped", 3, 0, "after 3 attempts", 3 * minRetryDelay},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setDBEnv(t, "1", "")
+			start := time.Now()
+			err := ConnectWithRetry(context.Background(), tt.maxAttempts, tt.baseDelay)
+			if err == nil {
+				Close()
+				t.Fatal("ConnectWithRetry succeeded against an unreachable server")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, want it to mention %q", err, tt.want)
+			}
+			if elapsed := time.Since(start); elapsed < tt.minElapsed {
+				t.Errorf("gave up after %s, want at least %s of backoff", elapsed, tt.minElapsed)
+			}
+		})
+	}
+}
+
+func TestConnectWithRetryCancelled(t *testing.T) {
+	setDBEnv(t, "1", "")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := ConnectWithRetry(ctx, 100, time.Hour)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error = %v, want context.DeadlineExceeded", err)
+	}
+	if !strings.Contains(err.Error(), "after 1 attempts") {
+		t.Errorf("error = %q, want it to mention the single attempt", err)
+	}
+}
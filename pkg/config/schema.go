@@ -0,0 +1,38 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ValidateSchema checks, without modifying anything, that the table of each
+// model exists and has a column for every persisted field. All mismatches
+// are reported together.
+func ValidateSchema(models ...interface{}) error {
+	d := GetBD()
+	if d == nil {
+		return ErrNotConnected
+	}
+
+	var errs []error
+	for _, m := range models {
+		scope := d.NewScope(m)
+		table := scope.TableName()
+		if !scope.Dialect().HasTable(table) {
+			errs = append(errs, fmt.Errorf("table %s is missing", table))
+			continue
+		}
+		for _, f := range scope.GetModelStruct().StructFields {
+			if !f.IsNormal || f.IsIgnored {
+				continue
+			}
+			if !scope.Dialect().HasColumn(table, f.DBName) {
+				errs = append(errs, fmt.Errorf("column %s.%s (field %s) is missing", table, f.DBName, f.Name))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("config: schema does not match models: %w", errors.Join(errs...))
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+//go:build sqlite
+
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type schemaBook struct {
+	ID    uint
+	Title string
+	ISBN  string
+	Notes string `gorm:"-"`
+}
+
+type schemaShelf struct {
+	ID uint
+}
+
+func TestValidateSchema(t *testing.T) {
+	connectTest(t, &schemaBook{})
+	if err := ValidateSchema(&schemaBook{}); err != nil {
+		t.Errorf("ValidateSchema() = %v for a migrated model", err)
+	}
+}
+
+func TestValidateSchemaMismatch(t *testing.T) {
+	d := connectTest(t)
+	if err := d.Exec("CREATE TABLE schema_books (id integer PRIMARY KEY, title varchar(255))").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	err := ValidateSchema(&schemaBook{}, &schemaShelf{})
+	if err == nil {
+		t.Fatal("ValidateSchema() = nil, want a mismatch")
+	}
+	for _, want := range []string{
+		"column schema_books.isbn (field ISBN) is missing",
+		"table schema_shelves is missing",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("ValidateSchema() = %v, want it to report %q", err, want)
+		}
+	}
+	for _, unwanted := range []string{"title", "notes"} {
+		if strings.Contains(err.Error(), "schema_books."+unwanted) {
+			t.Errorf("ValidateSchema() = %v, reported column %s", err, unwanted)
+		}
+	}
+	if d.HasTable(&schemaShelf{}) {
+		t.Error("ValidateSchema() created a table")
+	}
+}
+
+func TestValidateSchemaNotConnected(t *testing.T) {
+	if err := ValidateSchema(&schemaBook{}); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("ValidateSchema() = %v, want ErrNotConnected", err)
+	}
+}
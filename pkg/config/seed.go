@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Seeder loads a named set of seed data.
+type Seeder struct {
+	Name string
+	Run  func(tx *gorm.DB) error
+}
+
+type seederRun struct {
+	Name  string `gorm:"primary_key;size:255"`
+	RanAt time.Time
+}
+
+func (seederRun) TableName() string {
+	return "seeders"
+}
+
+// RunSeeders runs seeders in order, skipping names already recorded in the
+// seeders table. Each seeder runs in its own transaction together with its
+// bookkeeping row, and the first failure stops the run.
+func RunSeeders(seeders []Seeder) error {
+	d := GetBD()
+	if d == nil {
+		return ErrNotConnected
+	}
+	if err := d.AutoMigrate(&seederRun{}).Error; err != nil {
+		return fmt.Errorf("config: creating seeders table: %w", err)
+	}
+
+	for _, s := range seeders {
+		var count int
+		if err := d.Model(&seederRun{}).Where("name = ?", s.Name).Count(&count).Error; err != nil {
+			return fmt.Errorf("config: checking seeder %s: %w", s.Name, err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		err := Transaction(func(tx *gorm.DB) error {
+			if err := s.Run(tx); err != nil {
+				return err
+			}
+			return tx.Create(&seederRun{Name: s.Name, RanAt: time.Now()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("config: seeder %s failed: %w", s.Name, err)
+		}
+	}
+	return nil
+}
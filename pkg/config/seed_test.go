@@ -0,0 +1,80 @@
+//go:build sqlite
+
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+)
+
+type seedGenre struct {
+	ID   uint
+	Name string
+}
+
+func genreSeeder(name string, genres ...string) Seeder {
+	return Seeder{Name: name, Run: func(tx *gorm.DB) error {
+		for _, g := range genres {
+			if err := tx.Create(&seedGenre{Name: g}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}}
+}
+
+func TestRunSeedersTwice(t *testing.T) {
+	d := connectTest(t, &seedGenre{})
+	seeders := []Seeder{
+		genreSeeder("fiction", "Fantasy", "Mystery"),
+		genreSeeder("non-fiction", "History"),
+	}
+	for i := 0; i < 2; i++ {
+		if err := RunSeeders(seeders); err != nil {
+			t.Fatalf("run %d: %v", i+1, err)
+		}
+	}
+	if n := countRows(t, d, &seedGenre{}); n != 3 {
+		t.Errorf("%d genres after two runs, want 3", n)
+	}
+	if n := countRows(t, d, &seederRun{}); n != 2 {
+		t.Errorf("%d seeders recorded, want 2", n)
+	}
+}
+
+func TestRunSeedersFailure(t *testing.T) {
+	d := connectTest(t, &seedGenre{})
+	boom := errors.New("boom")
+	seeders := []Seeder{
+		genreSeeder("fiction", "Fantasy"),
+		{Name: "broken", Run: func(tx *gorm.DB) error {
+			tx.Create(&seedGenre{Name: "Half done"})
+			return boom
+		}},
+		genreSeeder("never", "Poetry"),
+	}
+
+	err := RunSeeders(seeders)
+	if !errors.Is(err, boom) || !strings.Contains(err.Error(), "seeder broken") {
+		t.Fatalf("RunSeeders() = %v, want the broken seeder's error", err)
+	}
+	var names []string
+	if err := d.Model(&seedGenre{}).Order("id").Pluck("name", &names).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 1 || names[0] != "Fantasy" {
+		t.Errorf("genres = %q, want only the first seeder's rows", names)
+	}
+	if n := countRows(t, d, &seederRun{}); n != 1 {
+		t.Errorf("%d seeders recorded, want 1", n)
+	}
+}
+
+func TestRunSeedersNotConnected(t *testing.T) {
+	if err := RunSeeders(nil); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("RunSeeders() = %v, want ErrNotConnected", err)
+	}
+}
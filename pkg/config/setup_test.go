@@ -0,0 +1,43 @@
+//go:build sqlite
+
+package config
+
+import (
+	"testing"
+
+	"github.com/jinzhu/gorm"
+)
+
+// connectTest swaps the package connection for a fresh in-memory SQLite
+// database with models migrated, and closes it when t ends. GORM's error
+// logging is turned off, for sessions from GetDBContext too, so expected
+// failures do not clutter the output.
+func connectTest(t *testing.T, models ...interface{}) *gorm.DB {
+	t.Helper()
+	SetLogMode(false)
+	d, err := ConnectForTesting()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		Close()
+		mu.Lock()
+		logMode = nil
+		mu.Unlock()
+	})
+	if err := d.AutoMigrate(models...).Error; err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// countRows returns the number of rows in the table of model, including
+// soft-deleted ones.
+func countRows(t *testing.T, d *gorm.DB, model interface{}) int {
+	t.Helper()
+	var n int
+	if err := d.Unscoped().Model(model).Count(&n).Error; err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
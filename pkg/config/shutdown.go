@@ -0,0 +1,55 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const shutdownPollInterval = 10 * time.Millisecond
+
+// Shutdown stops handing out the package connection, so GetBD returns nil
+// from then on, and waits for connections already in use to be returned to
+// the pool before closing it along with any replicas. If ctx ends first the
+// pool is closed anyway and an error wrapping ctx.Err() is returned.
+func Shutdown(ctx context.Context) error {
+	mu.Lock()
+	d := db
+	db = nil
+	mu.Unlock()
+
+	if d == nil {
+		return Close()
+	}
+
+	if inUse, err := waitIdle(ctx, d.DB()); err != nil {
+		d.Close()
+		Close()
+		return fmt.Errorf("config: shutdown with %d connections still in use: %w", inUse, err)
+	}
+
+	err := d.Close()
+	if cerr := Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// waitIdle waits until no connection of sqlDB is in use. If ctx ends first
+// it returns the number of connections still in use and ctx.Err().
+func waitIdle(ctx context.Context, sqlDB *sql.DB) (int, error) {
+	t := time.NewTicker(shutdownPollInterval)
+	defer t.Stop()
+	for {
+		inUse := sqlDB.Stats().InUse
+		if inUse == 0 {
+			return 0, nil
+		}
+		select {
+		case <-ctx.Done():
+			return inUse, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,57 @@
+//go:build sqlite
+
+package config
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShutdownTimeout(t *testing.T) {
+	d := connectTest(t)
+	conn, err := d.DB().Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "1 connections still in use") {
+		t.Fatalf("Shutdown() = %v, want a timeout with 1 connection in use", err)
+	}
+	if GetBD() != nil {
+		t.Error("GetBD() != nil after Shutdown")
+	}
+	if err := d.DB().Ping(); err == nil {
+		t.Error("pool still open after a timed-out Shutdown")
+	}
+}
+
+func TestShutdownWaitsForRelease(t *testing.T) {
+	d := connectTest(t)
+	conn, err := d.DB().Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.AfterFunc(30*time.Millisecond, func() { conn.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() = %v, want nil once the connection is released", err)
+	}
+	if err := d.DB().Ping(); err == nil {
+		t.Error("pool still open after Shutdown")
+	}
+}
+
+func TestShutdownNotConnected(t *testing.T) {
+	if err := Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown() = %v with nothing connected, want nil", err)
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+const startTimeKey = "config:start_time"
+
+var slowQueryThreshold atomic.Int64
+
+func init() {
+	cb := gorm.DefaultCallback
+	cb.Create().Before("gorm:begin_transaction").Register("config:start_timer", startTimer)
+	cb.Create().After("gorm:commit_or_rollback_transaction").Register("config:slow_query", logSlowQuery)
+	cb.Query().Before("gorm:query").Register("config:start_timer", startTimer)
+	cb.Query().After("gorm:after_query").Register("config:slow_query", logSlowQuery)
+	cb.RowQuery().Before("gorm:row_query").Register("config:start_timer", startTimer)
+	cb.RowQuery().After("gorm:row_query").Register("config:slow_query", logSlowQuery)
+	cb.Update().Before("gorm:begin_transaction").Register("config:start_timer", startTimer)
+	cb.Update().After("gorm:commit_or_rollback_transaction").Register("config:slow_query", logSlowQuery)
+	cb.Delete().Before("gorm:begin_transaction").Register("config:start_timer", startTimer)
+	cb.Delete().After("gorm:commit_or_rollback_transaction").Register("config:slow_query", logSlowQuery)
+}
+
+// SetSlowQueryThreshold logs every statement that takes longer than d. A
+// zero or negative d disables slow-query logging, which is the default.
+func SetSlowQueryThreshold(d time.Duration) {
+	slowQueryThreshold.Store(int64(d))
+}
+
+func startTimer(scope *gorm.Scope) {
+	scope.InstanceSet(startTimeKey, time.Now())
+}
+
+// elapsed returns the time since startTimer ran for scope.
+func elapsed(scope *gorm.Scope) (time.Duration, bool) {
+	v, ok := scope.InstanceGet(startTimeKey)
+	if !ok {
+		return 0, false
+	}
+	start, ok := v.(time.Time)
+	if !ok {
+		return 0, false
+	}
+	return time.Since(start), true
+}
+
+// logSlowQuery logs the statement with its placeholders intact so bound
+// values never reach the log.
+func logSlowQuery(scope *gorm.Scope) {
+	threshold := time.Duration(slowQueryThreshold.Load())
+	if threshold <= 0 {
+		return
+	}
+	if d, ok := elapsed(scope); ok && d > threshold {
+		logf("config: slow query (%s > %s): %s", d, threshold, scope.SQL)
+	}
+}
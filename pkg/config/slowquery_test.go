@@ -0,0 +1,38 @@
+//go:build sqlite
+
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type slowItem struct {
+	ID   uint
+	Name string
+}
+
+func TestSlowQueryLogging(t *testing.T) {
+	d := connectTest(t, &slowItem{})
+	l := useLogger(t)
+	t.Cleanup(func() { SetSlowQueryThreshold(0) })
+
+	d.Where("name = ?", "secret").Find(&[]slowItem{})
+	if lines := l.reset(); len(lines) != 0 {
+		t.Errorf("logged %q with slow-query logging disabled, want nothing", lines)
+	}
+
+	SetSlowQueryThreshold(time.Nanosecond)
+	d.Where("name = ?", "secret").Find(&[]slowItem{})
+	lines := l.reset()
+	if len(lines) != 1 {
+		t.Fatalf("logged %q, want one slow-query line", lines)
+	}
+	if !strings.Contains(lines[0], "slow query") || !strings.Contains(lines[0], `"slow_items"`) {
+		t.Errorf("logged %q, want the slow statement", lines[0])
+	}
+	if strings.Contains(lines[0], "secret") {
+		t.Errorf("logged %q, want bound values left out", lines[0])
+	}
+}
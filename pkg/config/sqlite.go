@@ -0,0 +1,34 @@
+//go:build sqlite
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+)
+
+// ConnectForTesting replaces the package connection with a fresh in-memory
+// SQLite database and returns it. It is only compiled with the sqlite build
+// tag so production binaries do not link the SQLite driver.
+func ConnectForTesting() (*gorm.DB, error) {
+	d, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		return nil, fmt.Errorf("config: failed to open sqlite connection: %w", err)
+	}
+	// Every connection to :memory: is a separate database, so pin the pool
+	// to a single connection.
+	d.DB().SetMaxOpenConns(1)
+
+	mu.Lock()
+	applySettings(d)
+	old := db
+	db = d
+	dialed = nil
+	mu.Unlock()
+	if old != nil {
+		old.Close()
+	}
+	return d, nil
+}
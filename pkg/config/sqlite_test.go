@@ -0,0 +1,34 @@
+//go:build sqlite
+
+package config_test
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/MikeOnBoard/go-bookstore/pkg/config"
+)
+
+type Book struct {
+	ID     uint
+	Name   string
+	Author string
+}
+
+func ExampleConnectForTesting() {
+	d, err := config.ConnectForTesting()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer config.Close()
+
+	d.AutoMigrate(&Book{})
+	d.Create(&Book{Name: "Dune", Author: "Frank Herbert"})
+	d.Create(&Book{Name: "Emma", Author: "Jane Austen"})
+
+	// Code using the package connection sees the same database.
+	var book Book
+	config.GetBD().Where("author = ?", "Jane Austen").First(&book)
+	fmt.Println(book.ID, book.Name)
+	// Output: 2 Emma
+}
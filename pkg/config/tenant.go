@@ -0,0 +1,96 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/jinzhu/gorm"
+)
+
+var schemaNameRe = regexp.MustCompile(`^[A-Za-z0-9_$]{1,64}$`)
+
+// UseSchema returns a session bound to a single pooled connection on which
+// USE `schema` has been issued, so every statement of the session runs
+// against that database. schema must be a plain identifier of letters,
+// digits, '_' or '$'; anything else is rejected and never sent to the
+// server.
+//
+// The connection is handed back to the pool, switched back to its original
+// database, when ctx is done or the session is closed with Close, whichever
+// comes first. Only mysql connections are supported.
+func UseSchema(ctx context.Context, schema string) (*gorm.DB, error) {
+	if !schemaNameRe.MatchString(schema) {
+		return nil, fmt.Errorf("config: invalid schema name %q", schema)
+	}
+	d := GetBD()
+	if d == nil {
+		return nil, ErrNotConnected
+	}
+	if name := d.Dialect().GetName(); name != DialectMySQL {
+		return nil, fmt.Errorf("config: UseSchema requires mysql, not %s", name)
+	}
+
+	conn, err := d.DB().Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("config: acquiring connection: %w", err)
+	}
+	var original sql.NullString
+	if err := conn.QueryRowContext(ctx, "SELECT DATABASE()").Scan(&original); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("config: reading current database: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, "USE `"+schema+"`"); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("config: switching to schema %s: %w", schema, err)
+	}
+
+	p := &pinnedConn{ctxDB: ctxDB{ctxConn{ctx, conn}, conn}, conn: conn, original: original}
+	context.AfterFunc(ctx, func() { p.Close() })
+
+	s, err := gorm.Open(DialectMySQL, p)
+	if err != nil {
+		p.Close()
+		return nil, err
+	}
+	mu.RLock()
+	applySettings(s)
+	mu.RUnlock()
+	return s.Set(contextKey, ctx), nil
+}
+
+// pinnedConn is a ctxDB over the connection UseSchema switched to the
+// tenant schema.
+type pinnedConn struct {
+	ctxDB
+	conn     *sql.Conn
+	original sql.NullString
+	once     sync.Once
+}
+
+// Close switches the connection back to its original database and returns
+// it to the pool. If that is not possible the connection is discarded
+// instead, so a tenant schema never leaks to other callers.
+func (p *pinnedConn) Close() error {
+	var err error
+	p.once.Do(func() {
+		restored := false
+		if p.original.Valid {
+			quoted := "`" + strings.ReplaceAll(p.original.String, "`", "``") + "`"
+			_, err = p.conn.ExecContext(context.Background(), "USE "+quoted)
+			restored = err == nil
+		}
+		if !restored {
+			// MySQL cannot deselect a database; returning ErrBadConn from
+			// Raw makes database/sql close the connection for good.
+			p.conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+			return
+		}
+		err = p.conn.Close()
+	})
+	return err
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// fakeMySQL is a database/sql connector standing in for a mysql server whose
+// current database is "shop". It records every statement with the
+// connection it ran on.
+type fakeMySQL struct {
+	mu    sync.Mutex
+	stmts []fakeStmt
+	conns int
+}
+
+type fakeStmt struct {
+	conn  int
+	query string
+}
+
+func (f *fakeMySQL) Connect(context.Context) (driver.Conn, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.conns++
+	return &fakeMySQLConn{f: f, id: f.conns}, nil
+}
+
+func (f *fakeMySQL) Driver() driver.Driver { return nil }
+
+func (f *fakeMySQL) record(conn int, query string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stmts = append(f.stmts, fakeStmt{conn, query})
+}
+
+func (f *fakeMySQL) statements() []fakeStmt {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]fakeStmt(nil), f.stmts...)
+}
+
+type fakeMySQLConn struct {
+	f  *fakeMySQL
+	id int
+}
+
+func (c *fakeMySQLConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *fakeMySQLConn) Close() error                        { return nil }
+func (c *fakeMySQLConn) Begin() (driver.Tx, error)           { return recordingTx{}, nil }
+
+func (c *fakeMySQLConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.f.record(c.id, query)
+	return recordingResult{}, nil
+}
+
+func (c *fakeMySQLConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.f.record(c.id, query)
+	if query == "SELECT DATABASE()" {
+		return &singleRow{value: "shop"}, nil
+	}
+	return emptyRows{}, nil
+}
+
+type singleRow struct {
+	value string
+	done  bool
+}
+
+func (r *singleRow) Columns() []string { return []string{"DATABASE()"} }
+func (r *singleRow) Close() error      { return nil }
+
+func (r *singleRow) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.value
+	return nil
+}
+
+// useFakeMySQL installs a mysql connection backed by a fakeMySQL as the
+// package connection until t ends.
+func useFakeMySQL(t *testing.T) *fakeMySQL {
+	t.Helper()
+	f := &fakeMySQL{}
+	d, err := gorm.Open(DialectMySQL, sql.OpenDB(f))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	db = d
+	mu.Unlock()
+	t.Cleanup(func() { Close() })
+	return f
+}
+
+func TestUseSchema(t *testing.T) {
+	f := useFakeMySQL(t)
+	s, err := UseSchema(context.Background(), "tenant_a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Exec("DELETE FROM books").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := f.statements()
+	want := []string{"SELECT DATABASE()", "USE `tenant_a`", "DELETE FROM books", "USE `shop`"}
+	var queries []string
+	for _, st := range got {
+		queries = append(queries, st.query)
+	}
+	if !reflect.DeepEqual(queries, want) {
+		t.Fatalf("UseSchema session ran %q, want %q", queries, want)
+	}
+	for _, st := range got[1:] {
+		if st.conn != got[0].conn {
+			t.Errorf("%q ran on connection %d, want the pinned connection %d", st.query, st.conn, got[0].conn)
+		}
+	}
+}
+
+func TestUseSchemaRestoresOnCancel(t *testing.T) {
+	f := useFakeMySQL(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := UseSchema(ctx, "tenant_a"); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	// Close runs in a goroutine started by context.AfterFunc; it is done
+	// once the connection is back in the pool.
+	sqlDB := GetBD().DB()
+	deadline := time.Now().Add(time.Second)
+	for sqlDB.Stats().InUse != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("connection not released within 1s of cancel")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	got := f.statements()
+	if last := got[len(got)-1].query; last != "USE `shop`" {
+		t.Errorf("last statement after cancel = %q, want the original database restored", last)
+	}
+}
+
+func TestUseSchemaInvalidName(t *testing.T) {
+	f := useFakeMySQL(t)
+	for _, name := range []string{
+		"foo; DROP TABLE",
+		"",
+		"tenant`a",
+		"tenant-a",
+		strings.Repeat("a", 65),
+	} {
+		if _, err := UseSchema(context.Background(), name); err == nil || !strings.Contains(err.Error(), "invalid schema name") {
+			t.Errorf("UseSchema(%q) = %v, want an invalid schema name error", name, err)
+		}
+	}
+	if got := f.statements(); len(got) != 0 {
+		t.Errorf("rejected names sent %v to the server", got)
+	}
+}
+
+func TestUseSchemaNotConnected(t *testing.T) {
+	if _, err := UseSchema(context.Background(), "tenant_a"); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("UseSchema() = %v, want ErrNotConnected", err)
+	}
+}
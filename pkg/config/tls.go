@@ -0,0 +1,59 @@
+package config
+
+import (
+	"crypto/sha1"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// TLSConfig points at the PEM files used to encrypt a MySQL connection.
+// CertFile and KeyFile are only needed when the server requires client
+// certificates.
+type TLSConfig struct {
+	CAFile     string `json:"caFile" yaml:"caFile"`
+	CertFile   string `json:"certFile" yaml:"certFile"`
+	KeyFile    string `json:"keyFile" yaml:"keyFile"`
+	ServerName string `json:"serverName" yaml:"serverName"`
+}
+
+// key is the name the config is registered under with the mysql driver. It
+// is derived from the fields so identical settings share one registration.
+func (t *TLSConfig) key() string {
+	sum := sha1.Sum([]byte(t.CAFile + "\x00" + t.CertFile + "\x00" + t.KeyFile + "\x00" + t.ServerName))
+	return fmt.Sprintf("config-%x", sum[:8])
+}
+
+// register loads the certificates and registers them with the mysql driver
+// under t.key().
+func (t *TLSConfig) register() error {
+	pem, err := os.ReadFile(t.CAFile)
+	if err != nil {
+		return fmt.Errorf("config: reading TLS CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return fmt.Errorf("config: no certificates found in TLS CA file %s", t.CAFile)
+	}
+	tc := &tls.Config{RootCAs: pool, ServerName: t.ServerName}
+
+	if t.CertFile != "" || t.KeyFile != "" {
+		if t.CertFile == "" || t.KeyFile == "" {
+			return errors.New("config: TLS CertFile and KeyFile must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return fmt.Errorf("config: loading TLS client certificate: %w", err)
+		}
+		tc.Certificates = []tls.Certificate{cert}
+	}
+
+	if err := mysql.RegisterTLSConfig(t.key(), tc); err != nil {
+		return fmt.Errorf("config: registering TLS config: %w", err)
+	}
+	return nil
+}
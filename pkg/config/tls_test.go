@@ -0,0 +1,83 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// writeCA writes a self-signed CA certificate in PEM form and returns its
+// path.
+func writeCA(t *testing.T) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return writeFile(t, "ca.pem", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+}
+
+func TestTLSConfigDSN(t *testing.T) {
+	cfg := Config{User: "u", Host: "db", Database: "shop", TLS: &TLSConfig{CAFile: writeCA(t), ServerName: "db.internal"}}
+	if err := cfg.prepare(); err != nil {
+		t.Fatal(err)
+	}
+	dsn := cfg.DSN()
+	if !strings.Contains(dsn, "tls="+cfg.TLS.key()) {
+		t.Fatalf("DSN() = %s, want a tls=%s parameter", dsn, cfg.TLS.key())
+	}
+	// The driver only accepts the name if it was registered.
+	parsed, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.TLS == nil || parsed.TLS.ServerName != "db.internal" {
+		t.Errorf("driver TLS config = %+v, want the registered one", parsed.TLS)
+	}
+
+	plain := Config{User: "u", Host: "db", Database: "shop"}
+	if dsn := plain.DSN(); strings.Contains(dsn, "tls=") {
+		t.Errorf("DSN() without TLS = %s, want no tls parameter", dsn)
+	}
+}
+
+func TestTLSConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		tls     TLSConfig
+		wantErr string
+	}{
+		{"missing CA file", TLSConfig{CAFile: "/nonexistent/ca.pem"}, "reading TLS CA file"},
+		{"not PEM", TLSConfig{CAFile: writeFile(t, "ca.txt", "not a certificate")}, "no certificates found"},
+		{"cert without key", TLSConfig{CAFile: writeCA(t), CertFile: "client.pem"}, "must be set together"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.tls.register()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("register() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/jinzhu/gorm"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const spanKey = "config:span"
+
+type tracerHolder struct {
+	tracer trace.Tracer
+}
+
+var tracing atomic.Pointer[tracerHolder]
+
+func init() {
+	cb := gorm.DefaultCallback
+	cb.Create().Before("gorm:begin_transaction").Register("config:start_span", startSpan("create"))
+	cb.Create().After("gorm:commit_or_rollback_transaction").Register("config:end_span", endSpan)
+	cb.Query().Before("gorm:query").Register("config:start_span", startSpan("query"))
+	cb.Query().After("gorm:after_query").Register("config:end_span", endSpan)
+	cb.RowQuery().Before("gorm:row_query").Register("config:start_span", startSpan("query"))
+	cb.RowQuery().After("gorm:row_query").Register("config:end_span", endSpan)
+	cb.Update().Before("gorm:begin_transaction").Register("config:start_span", startSpan("update"))
+	cb.Update().After("gorm:commit_or_rollback_transaction").Register("config:end_span", endSpan)
+	cb.Delete().Before("gorm:begin_transaction").Register("config:start_span", startSpan("delete"))
+	cb.Delete().After("gorm:commit_or_rollback_transaction").Register("config:end_span", endSpan)
+}
+
+// EnableTracing starts a span named "gorm.<operation>" around every
+// statement. Spans are children of the context attached with GetDBContext,
+// or root spans when there is none. Passing nil disables tracing.
+func EnableTracing(tracer trace.Tracer) {
+	if tracer == nil {
+		tracing.Store(nil)
+		return
+	}
+	tracing.Store(&tracerHolder{tracer: tracer})
+}
+
+func startSpan(operation string) func(*gorm.Scope) {
+	return func(scope *gorm.Scope) {
+		t := tracing.Load()
+		if t == nil {
+			return
+		}
+		ctx, ok := contextFrom(scope)
+		if !ok {
+			ctx = context.Background()
+		}
+		_, span := t.tracer.Start(ctx, "gorm."+operation, trace.WithSpanKind(trace.SpanKindClient))
+		scope.InstanceSet(spanKey, span)
+	}
+}
+
+func endSpan(scope *gorm.Scope) {
+	v, ok := scope.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span, ok := v.(trace.Span)
+	if !ok {
+		return
+	}
+	span.SetAttributes(
+		attribute.String("db.system", scope.Dialect().GetName()),
+		attribute.String("db.statement", scope.SQL),
+	)
+	if err := scope.DB().Error; err != nil && !gorm.IsRecordNotFoundError(err) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,84 @@
+//go:build sqlite
+
+package config
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type traceItem struct {
+	ID   uint
+	Name string
+}
+
+// useTracing records the spans of every statement until t ends.
+func useTracing(t *testing.T) (*tracetest.SpanRecorder, *sdktrace.TracerProvider) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	EnableTracing(tp.Tracer("config_test"))
+	t.Cleanup(func() { EnableTracing(nil) })
+	return sr, tp
+}
+
+func attr(span sdktrace.ReadOnlySpan, key attribute.Key) string {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value.AsString()
+		}
+	}
+	return ""
+}
+
+func TestTracingRootSpan(t *testing.T) {
+	d := connectTest(t, &traceItem{})
+	sr, _ := useTracing(t)
+
+	d.Create(&traceItem{Name: "a"})
+	d.Find(&[]traceItem{})
+
+	spans := sr.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	if spans[0].Name() != "gorm.create" || spans[1].Name() != "gorm.query" {
+		t.Errorf("span names = %q, %q; want gorm.create, gorm.query", spans[0].Name(), spans[1].Name())
+	}
+	q := spans[1]
+	if q.Parent().IsValid() {
+		t.Errorf("query span has parent %v, want a root span", q.Parent())
+	}
+	if got := attr(q, "db.system"); got != "sqlite3" {
+		t.Errorf("db.system = %q, want sqlite3", got)
+	}
+	if got := attr(q, "db.statement"); !strings.Contains(got, `FROM "trace_items"`) {
+		t.Errorf("db.statement = %q, want the query", got)
+	}
+}
+
+func TestTracingChildSpan(t *testing.T) {
+	connectTest(t, &traceItem{})
+	sr, tp := useTracing(t)
+
+	ctx, parent := tp.Tracer("config_test").Start(context.Background(), "request")
+	GetDBContext(ctx).Raw("SELECT * FROM missing_table").Scan(&[]traceItem{})
+	parent.End()
+
+	spans := sr.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	q := spans[0]
+	if q.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("query span parent = %v, want the request span", q.Parent().SpanID())
+	}
+	if q.Status().Code != codes.Error {
+		t.Errorf("status = %v, want an error for the failed query", q.Status())
+	}
+}
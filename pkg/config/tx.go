@@ -0,0 +1,35 @@
+package config
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Transaction runs fn inside a transaction on the package connection. The
+// transaction is committed when fn returns nil and rolled back when fn
+// returns an error or panics; panics propagate after the rollback.
+func Transaction(fn func(tx *gorm.DB) error) error {
+	d := GetBD()
+	if d == nil {
+		return ErrNotConnected
+	}
+
+	tx := d.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
@@ -0,0 +1,70 @@
+//go:build sqlite
+
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+)
+
+type txItem struct {
+	ID   uint
+	Name string
+}
+
+func TestTransactionCommits(t *testing.T) {
+	d := connectTest(t, &txItem{})
+	err := Transaction(func(tx *gorm.DB) error {
+		return tx.Create(&txItem{Name: "kept"}).Error
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := countRows(t, d, &txItem{}); n != 1 {
+		t.Errorf("%d rows after commit, want 1", n)
+	}
+}
+
+func TestTransactionRollsBackOnError(t *testing.T) {
+	d := connectTest(t, &txItem{})
+	errBoom := errors.New("boom")
+	err := Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&txItem{Name: "discarded"}).Error; err != nil {
+			return err
+		}
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("Transaction() = %v, want %v", err, errBoom)
+	}
+	if n := countRows(t, d, &txItem{}); n != 0 {
+		t.Errorf("%d rows after rollback, want 0", n)
+	}
+}
+
+func TestTransactionRollsBackOnPanic(t *testing.T) {
+	d := connectTest(t, &txItem{})
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("recovered %v, want the panic from fn", r)
+			}
+		}()
+		Transaction(func(tx *gorm.DB) error {
+			tx.Create(&txItem{Name: "discarded"})
+			panic("boom")
+		})
+	}()
+	if n := countRows(t, d, &txItem{}); n != 0 {
+		t.Errorf("%d rows after panic, want 0", n)
+	}
+}
+
+func TestTransactionNotConnected(t *testing.T) {
+	err := Transaction(func(*gorm.DB) error { return nil })
+	if !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Transaction() = %v, want ErrNotConnected", err)
+	}
+}
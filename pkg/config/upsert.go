@@ -0,0 +1,71 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Upsert inserts value or, when it collides with an existing primary or
+// unique key, updates updateColumns of that row instead, using MySQL's
+// INSERT ... ON DUPLICATE KEY UPDATE. With no updateColumns every
+// non-primary column except created_at is updated. Column names must be
+// columns of the model. Only mysql connections are supported.
+func Upsert(ctx context.Context, value interface{}, updateColumns []string) error {
+	d := GetDBContext(ctx)
+	if d == nil {
+		return ErrNotConnected
+	}
+	if name := d.Dialect().GetName(); name != DialectMySQL {
+		return fmt.Errorf("config: Upsert requires mysql, not %s", name)
+	}
+
+	scope := d.NewScope(value)
+	columns := map[string]*gorm.StructField{}
+	for _, f := range scope.GetModelStruct().StructFields {
+		if f.IsNormal && !f.IsIgnored {
+			columns[f.DBName] = f
+		}
+	}
+
+	if len(updateColumns) == 0 {
+		for _, f := range scope.GetModelStruct().StructFields {
+			if f.IsNormal && !f.IsIgnored && !f.IsPrimaryKey && f.DBName != "created_at" {
+				updateColumns = append(updateColumns, f.DBName)
+			}
+		}
+	}
+
+	var sets []string
+	for _, c := range updateColumns {
+		f, ok := columns[c]
+		if !ok {
+			return fmt.Errorf("config: Upsert: %s has no column %q", scope.TableName(), c)
+		}
+		q := scope.Quote(f.DBName)
+		sets = append(sets, q+" = VALUES("+q+")")
+	}
+	// Make LAST_INSERT_ID report the existing row on update so GORM fills
+	// in value's ID either way.
+	if pk := scope.PrimaryField(); pk != nil && isInteger(pk.Field.Kind()) {
+		q := scope.Quote(pk.DBName)
+		sets = append(sets, q+" = LAST_INSERT_ID("+q+")")
+	}
+	if len(sets) == 0 {
+		return fmt.Errorf("config: Upsert: no columns to update for %s", scope.TableName())
+	}
+
+	return d.Set("gorm:insert_option", "ON DUPLICATE KEY UPDATE "+strings.Join(sets, ", ")).Create(value).Error
+}
+
+func isInteger(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+//go:build mysql
+
+package config
+
+import (
+	"context"
+	"testing"
+)
+
+// connectMySQL connects with the DB_* variables, as Connect does, and
+// migrates models into fresh tables that are dropped when t ends.
+func connectMySQL(t *testing.T, models ...interface{}) {
+	t.Helper()
+	if err := Connect(); err != nil {
+		t.Fatalf("connecting to the DB_* server: %v", err)
+	}
+	d := GetBD()
+	t.Cleanup(func() {
+		d.DropTableIfExists(models...)
+		Close()
+	})
+	if err := d.DropTableIfExists(models...).AutoMigrate(models...).Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpsertMySQL(t *testing.T) {
+	connectMySQL(t, &upsertItem{})
+	ctx := context.Background()
+
+	first := upsertItem{SKU: "A-1", Name: "Lamp"}
+	if err := Upsert(ctx, &first, nil); err != nil {
+		t.Fatal(err)
+	}
+	if first.ID == 0 {
+		t.Fatal("insert path left ID unset")
+	}
+
+	second := upsertItem{SKU: "A-1", Name: "Desk lamp"}
+	if err := Upsert(ctx, &second, []string{"name"}); err != nil {
+		t.Fatal(err)
+	}
+	if second.ID != first.ID {
+		t.Errorf("update path set ID %d, want the existing row's %d", second.ID, first.ID)
+	}
+
+	var rows []upsertItem
+	if err := GetBD().Find(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("%d rows after upserting the same SKU twice, want 1", len(rows))
+	}
+	if rows[0].Name != "Desk lamp" {
+		t.Errorf("name = %q, want the upserted Desk lamp", rows[0].Name)
+	}
+}
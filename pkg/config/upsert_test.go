@@ -0,0 +1,88 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+type upsertItem struct {
+	ID        uint
+	SKU       string `gorm:"unique_index"`
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// insertStatement returns the INSERT recorded by f.
+func insertStatement(t *testing.T, f *fakeMySQL) string {
+	t.Helper()
+	for _, st := range f.statements() {
+		if strings.HasPrefix(st.query, "INSERT") {
+			return st.query
+		}
+	}
+	t.Fatal("no INSERT statement was run")
+	return ""
+}
+
+func TestUpsert(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns []string
+		want    string
+	}{
+		{
+			name: "all columns",
+			want: "ON DUPLICATE KEY UPDATE `sku` = VALUES(`sku`), `name` = VALUES(`name`), `updated_at` = VALUES(`updated_at`), `id` = LAST_INSERT_ID(`id`)",
+		},
+		{
+			name:    "selected columns",
+			columns: []string{"name"},
+			want:    "ON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `id` = LAST_INSERT_ID(`id`)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := useFakeMySQL(t)
+			if err := Upsert(context.Background(), &upsertItem{SKU: "A-1", Name: "Lamp"}, tt.columns); err != nil {
+				t.Fatal(err)
+			}
+			if got := insertStatement(t, f); !strings.HasSuffix(got, tt.want) {
+				t.Errorf("Upsert ran %q, want it to end in %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpsertUnknownColumn(t *testing.T) {
+	f := useFakeMySQL(t)
+	err := Upsert(context.Background(), &upsertItem{SKU: "A-1"}, []string{"price"})
+	if err == nil || !strings.Contains(err.Error(), `upsert_items has no column "price"`) {
+		t.Fatalf("Upsert() = %v, want an unknown column error", err)
+	}
+	if got := f.statements(); len(got) != 0 {
+		t.Errorf("a rejected Upsert ran %v", got)
+	}
+}
+
+func TestUpsertRequiresMySQL(t *testing.T) {
+	// The dialect check runs before anything reaches the connector.
+	d, err := gorm.Open(DialectPostgres, sql.OpenDB(&fakeMySQL{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	db = d
+	mu.Unlock()
+	t.Cleanup(func() { Close() })
+
+	err = Upsert(context.Background(), &upsertItem{SKU: "A-1"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "requires mysql, not postgres") {
+		t.Errorf("Upsert() = %v, want a dialect error", err)
+	}
+}
@@ -1,12 +1,12 @@
 package models
 
 import (
+	"log"
+
 	"github.com/MikeOnBoard/go-bookstore/pkg/config"
 	"github.com/jinzhu/gorm"
 )
 
-var db *gorm.DB
-
 type Book struct {
 	gorm.Model
 	Name        string `gorm:"" json:"name"`
@@ -15,13 +15,15 @@ type Book struct {
 }
 
 func init() {
-	config.Connect()
-	db = config.GetBD()
-	db.AutoMigrate(&Book{})
+	if err := config.Connect(); err != nil {
+		log.Fatal(err)
+	}
+	config.GetBD().AutoMigrate(&Book{})
 
 }
 
 func (b *Book) CreateBook() *Book {
+	db := config.GetBD()
 	db.NewRecord(b)
 	db.Create(b)
 	return b
@@ -29,18 +31,18 @@ func (b *Book) CreateBook() *Book {
 
 func GetAllBooks() []Book {
 	var Books []Book
-	db.Find(&Books)
+	config.GetBD().Find(&Books)
 	return (Books)
 }
 
 func GetBookById(Id int64) (*Book, *gorm.DB) {
 	var getBook Book
-	db := db.Where("ID=?", Id).Find(&getBook)
+	db := config.GetBD().Where("ID=?", Id).Find(&getBook)
 	return &getBook, db
 }
 
 func DeleteBook(ID int64) Book {
 	var book Book
-	db.Where("ID=?", ID).Delete(book)
+	config.GetBD().Where("ID=?", ID).Delete(book)
 	return book
 }